@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -11,6 +13,59 @@ type Config struct {
 	CloudinaryAPIKey    string
 	CloudinaryAPISecret string
 	JWTSecret           string
+
+	// CommentEditWindowMinutes is how long after posting a comment can still be edited
+	CommentEditWindowMinutes int
+
+	// ReactionEmojis are the emoji readers can react with, in addition to "like"
+	ReactionEmojis []string
+
+	// View tracking: a visitor is counted once per post per dedup window,
+	// and buffered views are written every flush interval or batch size
+	ViewDedupMinutes   int
+	ViewFlushSeconds   int
+	ViewFlushBatchSize int
+
+	// ReviewWorkflowEnabled requires authors to submit posts for review;
+	// only editors can then publish them
+	ReviewWorkflowEnabled bool
+
+	// TrashRetentionDays is how long trashed posts are kept before they are
+	// purged for good (0 disables the purge)
+	TrashRetentionDays int
+
+	// CronSecret authenticates scheduled jobs (Vercel Cron) calling /api/cron/*
+	CronSecret string
+
+	// RequireIfMatch rejects post updates and deletes sent without an
+	// If-Match header (428), instead of only checking it when present
+	RequireIfMatch bool
+
+	// SiteURL is the public frontend base URL (the API and the site live on
+	// different hosts) used for links in feeds and the sitemap; SiteTitle
+	// names the site in feeds
+	SiteURL   string
+	SiteTitle string
+
+	// FeedFullContent puts the full rendered post in feeds instead of the excerpt
+	FeedFullContent bool
+
+	// SupportedLocales are the languages posts can be written in; readers
+	// whose language isn't supported get DefaultLocale
+	DefaultLocale    string
+	SupportedLocales []string
+
+	// ProxyHeader is the header the reverse proxy puts the client IP in
+	// (e.g. X-Real-IP); empty uses the connection's address. It is only
+	// believed from TrustedProxies (IPs or CIDRs), or from anyone when that
+	// is empty, which is only safe on platforms that overwrite the header.
+	ProxyHeader    string
+	TrustedProxies []string
+
+	// UnlockMaxAttempts is how many wrong passwords a client may send for a
+	// protected post within UnlockWindowMinutes before it gets 429
+	UnlockMaxAttempts   int
+	UnlockWindowMinutes int
 }
 
 var AppConfig *Config
@@ -27,6 +82,35 @@ func LoadConfig() {
 		CloudinaryAPIKey:    getEnvOrDefault("CLOUDINARY_API_KEY", "secret_default_api_key"),
 		CloudinaryAPISecret: getEnvOrDefault("CLOUDINARY_API_SECRET", "secret_default_api_secret"),
 		JWTSecret:           getEnvOrDefault("JWT_SECRET", "secret_default_jwt_secret"),
+
+		CommentEditWindowMinutes: getEnvIntOrDefault("COMMENT_EDIT_WINDOW_MINUTES", 15),
+		ReactionEmojis:           getEnvListOrDefault("REACTION_EMOJIS", []string{"❤️", "😂", "😮", "😢", "🔥"}),
+		ViewDedupMinutes:         getEnvIntOrDefault("VIEW_DEDUP_MINUTES", 30),
+		ViewFlushSeconds:         getEnvIntOrDefault("VIEW_FLUSH_SECONDS", 30),
+		ViewFlushBatchSize:       getEnvIntOrDefault("VIEW_FLUSH_BATCH_SIZE", 100),
+		ReviewWorkflowEnabled:    getEnvBoolOrDefault("REVIEW_WORKFLOW_ENABLED", false),
+		TrashRetentionDays:       getEnvIntOrDefault("TRASH_RETENTION_DAYS", 30),
+		CronSecret:               getEnvOrDefault("CRON_SECRET", ""),
+		RequireIfMatch:           getEnvBoolOrDefault("REQUIRE_IF_MATCH", false),
+		SiteURL:                  strings.TrimRight(getEnvOrDefault("SITE_URL", "http://localhost:3000"), "/"),
+		SiteTitle:                getEnvOrDefault("SITE_TITLE", "KataGenzi"),
+		FeedFullContent:          getEnvBoolOrDefault("FEED_FULL_CONTENT", false),
+		DefaultLocale:            strings.ToLower(getEnvOrDefault("DEFAULT_LOCALE", "id")),
+		SupportedLocales:         getEnvListOrDefault("SUPPORTED_LOCALES", []string{"id", "en"}),
+		ProxyHeader:              getEnvOrDefault("PROXY_HEADER", ""),
+		TrustedProxies:           getEnvListOrDefault("TRUSTED_PROXIES", nil),
+		UnlockMaxAttempts:        getEnvIntOrDefault("UNLOCK_MAX_ATTEMPTS", 5),
+		UnlockWindowMinutes:      getEnvIntOrDefault("UNLOCK_WINDOW_MINUTES", 15),
+	}
+
+	// The default locale is always supported
+	supported := false
+	for i, locale := range AppConfig.SupportedLocales {
+		AppConfig.SupportedLocales[i] = strings.ToLower(locale)
+		supported = supported || AppConfig.SupportedLocales[i] == AppConfig.DefaultLocale
+	}
+	if !supported {
+		AppConfig.SupportedLocales = append([]string{AppConfig.DefaultLocale}, AppConfig.SupportedLocales...)
 	}
 
 	log.Println("✓ Configuration loaded successfully")
@@ -44,3 +128,50 @@ func getEnvOrDefault(key, defaultValue string) string {
 	log.Printf("  Using env var for %s", key)
 	return value
 }
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Printf("  Using default value for %s", key)
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("  Invalid integer for %s, using default value", key)
+		return defaultValue
+	}
+	log.Printf("  Using env var for %s", key)
+	return parsed
+}
+
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Printf("  Using default value for %s", key)
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("  Invalid boolean for %s, using default value", key)
+		return defaultValue
+	}
+	log.Printf("  Using env var for %s", key)
+	return parsed
+}
+
+// getEnvListOrDefault reads a comma-separated list, dropping empty items
+func getEnvListOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Printf("  Using default value for %s", key)
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	log.Printf("  Using env var for %s", key)
+	return list
+}
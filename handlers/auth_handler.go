@@ -118,6 +118,7 @@ func RegisterUser(c *fiber.Ctx) error {
 		FullName:     req.FullName,
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
+		Role:         models.RoleAuthor,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -157,6 +158,7 @@ type JwtCustomClaims struct {
 	UserID   string `json:"user_id"`
 	FullName string `json:"full_name"`
 	Email    string `json:"email"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -179,6 +181,7 @@ func generateJWT(user *models.User) (string, error) {
 		UserID:   user.ID.String(),
 		FullName: user.FullName,
 		Email:    user.Email,
+		Role:     user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   user.ID.String(),                                   // ← SET SUBJECT FIELD FOR MIDDLEWARE
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 72)), // Token is valid for 72 hours
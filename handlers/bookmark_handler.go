@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReadingListRequest is the struct for creating or updating a reading list
+type ReadingListRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// ReadingListItemRequest is the struct for adding a post to a reading list
+type ReadingListItemRequest struct {
+	PostID string `json:"post_id" validate:"required,uuid"`
+}
+
+// newShareToken generates the random token used in public reading list URLs
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// listPublishedPosts loads the published posts of a reading list in order.
+// Posts that were trashed or unpublished after being added are skipped.
+func listPublishedPosts(listID uuid.UUID) ([]models.Post, error) {
+	posts := []models.Post{}
+	err := database.DB.Model(&models.Post{}).
+		Joins("JOIN reading_list_items ON reading_list_items.post_id = posts.id").
+		Where("reading_list_items.reading_list_id = ?", listID).
+		Scopes(publicPosts).
+		Preload("Author").
+		Preload("Tags").
+		Omit("content", "content_html", "toc").
+		Order("reading_list_items.position ASC").
+		Order("reading_list_items.created_at ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// findOwnReadingList loads the reading list in :id and checks the caller owns it
+func findOwnReadingList(c *fiber.Ctx) (*models.ReadingList, error) {
+	listID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid reading list ID format", "error": err.Error(),
+		})
+	}
+
+	var list models.ReadingList
+	if err := database.DB.First(&list, listID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Reading list not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+
+	// Other users' lists are reported as missing rather than forbidden
+	userID, _ := currentUserID(c)
+	if list.UserID != userID {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Reading list not found",
+		})
+	}
+	return &list, nil
+}
+
+// AddBookmark is the handler for POST /api/posts/:id/bookmark
+func AddBookmark(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid user data in token",
+		})
+	}
+
+	// 1. Only published posts can be bookmarked
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if post.Status != "publish" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Post not found",
+		})
+	}
+
+	// 2. Save the bookmark (adding it twice is a no-op)
+	bookmark := models.Bookmark{ID: uuid.New(), UserID: userID, PostID: post.ID}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&bookmark).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to bookmark post", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post bookmarked successfully",
+		"data":    fiber.Map{"post_id": post.ID, "bookmarked": true},
+	})
+}
+
+// RemoveBookmark is the handler for DELETE /api/posts/:id/bookmark
+func RemoveBookmark(c *fiber.Ctx) error {
+	userID, _ := currentUserID(c)
+	postID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid post ID format", "error": err.Error(),
+		})
+	}
+
+	// Works even if the post has since been trashed or deleted
+	if err := database.DB.Where("user_id = ? AND post_id = ?", userID, postID).
+		Delete(&models.Bookmark{}).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to remove bookmark", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Bookmark removed successfully",
+		"data":    fiber.Map{"post_id": postID, "bookmarked": false},
+	})
+}
+
+// GetBookmarks is the handler for GET /api/bookmarks (PROTECTED)
+// Returns the caller's bookmarked posts, most recently bookmarked first.
+func GetBookmarks(c *fiber.Ctx) error {
+	userID, _ := currentUserID(c)
+
+	page, err := parsePageParams(c)
+	if err != nil || page.cursorMode() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters",
+		})
+	}
+
+	// 1. Bookmarked posts that are still published
+	query := database.DB.Model(&models.Post{}).
+		Joins("JOIN bookmarks ON bookmarks.post_id = posts.id").
+		Where("bookmarks.user_id = ?", userID).
+		Scopes(publicPosts)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to count bookmarks", "error": err.Error(),
+		})
+	}
+
+	posts := []models.Post{}
+	if err := query.
+		Preload("Author").
+		Preload("Tags").
+		Omit("content", "content_html", "toc").
+		Order("bookmarks.created_at DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&posts).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve bookmarks", "error": err.Error(),
+		})
+	}
+
+	decoratePosts(c, posts)
+
+	// 2. Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Bookmarks retrieved successfully",
+		"data":    posts,
+		"meta": fiber.Map{
+			"total":  total,
+			"limit":  page.Limit,
+			"offset": page.Offset,
+		},
+	})
+}
+
+// GetReadingLists is the handler for GET /api/reading-lists (PROTECTED)
+func GetReadingLists(c *fiber.Ctx) error {
+	userID, _ := currentUserID(c)
+
+	// 1. Load the caller's lists
+	lists := []models.ReadingList{}
+	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&lists).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve reading lists", "error": err.Error(),
+		})
+	}
+
+	// 2. Count published posts per list in one query
+	if len(lists) > 0 {
+		ids := make([]uuid.UUID, len(lists))
+		for i := range lists {
+			ids[i] = lists[i].ID
+		}
+		var rows []struct {
+			ReadingListID uuid.UUID
+			Count         int64
+		}
+		database.DB.Model(&models.ReadingListItem{}).
+			Select("reading_list_items.reading_list_id, COUNT(*) AS count").
+			Joins("JOIN posts ON posts.id = reading_list_items.post_id AND posts.deleted_at IS NULL").
+			Where("reading_list_items.reading_list_id IN ?", ids).
+			Scopes(publicPosts).
+			Group("reading_list_items.reading_list_id").
+			Scan(&rows)
+		counts := make(map[uuid.UUID]int64, len(rows))
+		for _, row := range rows {
+			counts[row.ReadingListID] = row.Count
+		}
+		for i := range lists {
+			lists[i].PostCount = counts[lists[i].ID]
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reading lists retrieved successfully",
+		"data":    lists,
+	})
+}
+
+// CreateReadingList is the handler for POST /api/reading-lists (PROTECTED)
+func CreateReadingList(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid user data in token",
+		})
+	}
+
+	// 1. Parse and validate the request body
+	req := new(ReadingListRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	// 2. Create the list
+	list := models.ReadingList{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := setReadingListVisibility(&list, req.IsPublic); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to generate share token", "error": err.Error(),
+		})
+	}
+	if err := database.DB.Create(&list).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to create reading list", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reading list created successfully",
+		"data":    list,
+	})
+}
+
+// setReadingListVisibility makes a list public (issuing a share token the
+// first time) or private (revoking the token so old links stop working)
+func setReadingListVisibility(list *models.ReadingList, public bool) error {
+	list.IsPublic = public
+	if !public {
+		list.ShareToken = nil
+		return nil
+	}
+	if list.ShareToken == nil {
+		token, err := newShareToken()
+		if err != nil {
+			return err
+		}
+		list.ShareToken = &token
+	}
+	return nil
+}
+
+// GetReadingList is the handler for GET /api/reading-lists/:id (PROTECTED)
+func GetReadingList(c *fiber.Ctx) error {
+	// 1. Find the list
+	list, err := findOwnReadingList(c)
+	if list == nil {
+		return err
+	}
+
+	// 2. Load its published posts
+	posts, err := listPublishedPosts(list.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve reading list posts", "error": err.Error(),
+		})
+	}
+	decoratePosts(c, posts)
+	list.PostCount = int64(len(posts))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reading list retrieved successfully",
+		"data":    fiber.Map{"list": list, "posts": posts},
+	})
+}
+
+// UpdateReadingList is the handler for PUT /api/reading-lists/:id (PROTECTED)
+func UpdateReadingList(c *fiber.Ctx) error {
+	// 1. Find the list
+	list, err := findOwnReadingList(c)
+	if list == nil {
+		return err
+	}
+
+	// 2. Parse and validate the request body
+	req := new(ReadingListRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	// 3. Save
+	list.Name = req.Name
+	list.Description = req.Description
+	if err := setReadingListVisibility(list, req.IsPublic); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to generate share token", "error": err.Error(),
+		})
+	}
+	if err := database.DB.Save(list).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update reading list", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reading list updated successfully",
+		"data":    list,
+	})
+}
+
+// DeleteReadingList is the handler for DELETE /api/reading-lists/:id (PROTECTED)
+func DeleteReadingList(c *fiber.Ctx) error {
+	// 1. Find the list
+	list, err := findOwnReadingList(c)
+	if list == nil {
+		return err
+	}
+
+	// 2. Delete the list and its items together
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("reading_list_id = ?", list.ID).Delete(&models.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(list).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to delete reading list", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reading list deleted successfully",
+	})
+}
+
+// AddReadingListItem is the handler for POST /api/reading-lists/:id/posts (PROTECTED)
+func AddReadingListItem(c *fiber.Ctx) error {
+	// 1. Find the list
+	list, err := findOwnReadingList(c)
+	if list == nil {
+		return err
+	}
+
+	// 2. Parse and validate the request body
+	req := new(ReadingListItemRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	// 3. Only published posts can be added
+	var post models.Post
+	if err := database.DB.Scopes(publicPosts).First(&post, "id = ?", req.PostID).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Post not found",
+		})
+	}
+
+	// 4. Append at the end of the list (adding it twice is a no-op)
+	var last int
+	database.DB.Model(&models.ReadingListItem{}).
+		Where("reading_list_id = ?", list.ID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&last)
+	item := models.ReadingListItem{ReadingListID: list.ID, PostID: post.ID, Position: last + 1}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&item).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to add post to reading list", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post added to reading list successfully",
+	})
+}
+
+// RemoveReadingListItem is the handler for DELETE /api/reading-lists/:id/posts/:postId (PROTECTED)
+func RemoveReadingListItem(c *fiber.Ctx) error {
+	// 1. Find the list
+	list, err := findOwnReadingList(c)
+	if list == nil {
+		return err
+	}
+
+	postID, err := uuid.Parse(c.Params("postId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid post ID format", "error": err.Error(),
+		})
+	}
+
+	// 2. Remove the item
+	if err := database.DB.Where("reading_list_id = ? AND post_id = ?", list.ID, postID).
+		Delete(&models.ReadingListItem{}).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to remove post from reading list", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post removed from reading list successfully",
+	})
+}
+
+// GetSharedReadingList is the handler for GET /api/shared/reading-lists/:token (PUBLIC)
+func GetSharedReadingList(c *fiber.Ctx) error {
+	// 1. Find the public list by its share token
+	var list models.ReadingList
+	err := database.DB.Preload("User").
+		Where("share_token = ? AND is_public = ?", c.Params("token"), true).
+		First(&list).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Reading list not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+
+	// 2. Load its published posts
+	posts, err := listPublishedPosts(list.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve reading list posts", "error": err.Error(),
+		})
+	}
+	decoratePosts(c, posts)
+	list.PostCount = int64(len(posts))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reading list retrieved successfully",
+		"data":    fiber.Map{"list": list, "posts": posts},
+	})
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BulkPostRequest is the struct for parsing and validating a bulk operation.
+// At most 100 posts can be processed per request.
+type BulkPostRequest struct {
+	IDs      []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+	Action   string   `json:"action" validate:"required,oneof=publish draft trash restore add_tags remove_tags change_category"`
+	Tags     []string `json:"tags" validate:"required_if=Action add_tags,required_if=Action remove_tags,omitempty,dive,min=1"`
+	Category string   `json:"category" validate:"omitempty,min=3"` // Name or slug; only editors may name a new one
+	// CategoryID takes precedence over Category; change_category needs one of them
+	CategoryID *uuid.UUID `json:"category_id"`
+}
+
+// bulkResult is the outcome of the bulk action for one post
+type bulkResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"` // Post status after the action
+	Error   string `json:"error,omitempty"`
+}
+
+// bulkStatusActions maps status actions to their target status
+var bulkStatusActions = map[string]string{
+	"publish": models.PostStatusPublish,
+	"draft":   models.PostStatusDraft,
+	"trash":   models.PostStatusTrash,
+}
+
+// errBulkForbidden is reported for posts the caller may not edit
+var errBulkForbidden = errors.New("you are not authorized to edit this post")
+
+// BulkUpdatePosts is the handler for POST /api/posts/bulk
+// Runs one action over many posts in a single transaction. Each post is
+// authorized like UpdatePost; a failing post is rolled back on its own and
+// reported in the results without aborting the others.
+func BulkUpdatePosts(c *fiber.Ctx) error {
+	// 1. Parse and validate the request body
+	req := new(BulkPostRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if req.Action == "change_category" && req.Category == "" && req.CategoryID == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "change_category needs a category or category_id",
+		})
+	}
+
+	// 2. Run every item inside one transaction, with a savepoint per item
+	results := make([]bulkResult, 0, len(req.IDs))
+	succeeded := 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Tags and the category are resolved once for the whole batch.
+		// Removing never creates tags: names that don't exist are simply ignored.
+		var tags []*models.Tag
+		var category *models.Category
+		switch req.Action {
+		case "add_tags":
+			var err error
+			if tags, err = resolveTags(tx, req.Tags); err != nil {
+				return err
+			}
+		case "remove_tags":
+			if err := tx.Where("name IN ?", normalizeTagNames(req.Tags)).Find(&tags).Error; err != nil {
+				return err
+			}
+		case "change_category":
+			var err error
+			if category, err = resolvePostCategory(c, tx, req.CategoryID, req.Category); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range req.IDs {
+			if err := tx.SavePoint("bulk_item").Error; err != nil {
+				return err
+			}
+
+			status, err := applyBulkAction(c, tx, id, req, tags, category)
+			if err != nil {
+				if rbErr := tx.RollbackTo("bulk_item").Error; rbErr != nil {
+					return rbErr
+				}
+				results = append(results, bulkResult{ID: id, Success: false, Error: err.Error()})
+				continue
+			}
+			succeeded++
+			results = append(results, bulkResult{ID: id, Success: true, Status: status})
+		}
+		return nil
+	})
+	if errors.Is(err, errUnknownCategory) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Bulk operation failed", "error": err.Error(),
+		})
+	}
+	invalidateRelatedPosts()
+
+	// 3. Return the per-item results
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Bulk operation completed",
+		"data":    results,
+		"meta": fiber.Map{
+			"action":    req.Action,
+			"total":     len(req.IDs),
+			"succeeded": succeeded,
+			"failed":    len(req.IDs) - succeeded,
+		},
+	})
+}
+
+// applyBulkAction performs the bulk action on one post and returns its new status
+func applyBulkAction(c *fiber.Ctx, tx *gorm.DB, id string, req *BulkPostRequest, tags []*models.Tag, category *models.Category) (string, error) {
+	postID, err := uuid.Parse(id)
+	if err != nil {
+		return "", errors.New("invalid post ID format")
+	}
+
+	// 1. Find the post and authorize it like UpdatePost
+	var post models.Post
+	if err := tx.First(&post, postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", errors.New("post not found")
+		}
+		return "", err
+	}
+	if !canEditPost(c, &post) {
+		return "", errBulkForbidden
+	}
+
+	// 2. Apply the action. Edits keep the status, which still has to be
+	// allowed: under review, authors can't change a published post in place.
+	actor := actorFor(c, &post)
+	if _, ok := bulkStatusActions[req.Action]; !ok && req.Action != "restore" {
+		if err := checkPostTransition(post.Status, post.Status, actor); err != nil {
+			return "", err
+		}
+	}
+	switch req.Action {
+	case "publish", "draft", "trash":
+		target := bulkStatusActions[req.Action]
+		if err := checkPostTransition(post.Status, target, actor); err != nil {
+			return "", err
+		}
+		applyPostStatus(&post, target)
+	case "restore":
+		if post.Status != models.PostStatusTrash {
+			return "", errors.New("post is not in the trash")
+		}
+		applyPostStatus(&post, restoreTarget(&post, actor))
+	case "add_tags":
+		if err := tx.Model(&post).Association("Tags").Append(tags); err != nil {
+			return "", err
+		}
+	case "remove_tags":
+		if err := tx.Model(&post).Association("Tags").Delete(tags); err != nil {
+			return "", err
+		}
+	case "change_category":
+		setPostCategory(&post, category)
+	}
+
+	// 3. Save the post row
+	post.UpdatedAt = time.Now()
+	if err := savePost(tx, &post); err != nil {
+		return "", err
+	}
+	return post.Status, nil
+}
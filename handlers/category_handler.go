@@ -0,0 +1,473 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+	"github.com/mohamadsolkhannawawi/article-backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CategoryRequest is the struct for creating or updating a category
+type CategoryRequest struct {
+	Name        string     `json:"name" validate:"required,min=3,max=100"`
+	Slug        string     `json:"slug" validate:"omitempty,max=120"` // Derived from the name if empty
+	Description string     `json:"description" validate:"omitempty,max=1000"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	SortOrder   int        `json:"sort_order"`
+}
+
+// categoryTreeQuery selects a category and all of its descendants.
+// UNION (not UNION ALL) stops the recursion even if the tree had a cycle.
+const categoryTreeQuery = `
+WITH RECURSIVE tree AS (
+	SELECT id FROM categories WHERE id = ?
+	UNION
+	SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+)
+SELECT id FROM tree`
+
+// categorySlug slugifies the name, falling back to a random slug for
+// names without any letters or digits
+func categorySlug(name string) string {
+	if slug := utils.Slugify(name); slug != "" {
+		return slug
+	}
+	return "category-" + uuid.NewString()[:8]
+}
+
+// errUnknownCategory is returned for a post category that doesn't exist
+// when the caller may not create it
+var errUnknownCategory = errors.New("unknown category: use the category_id or slug of an existing category")
+
+// resolveCategory finds a category by name or slug, case-insensitively,
+// so "Tech" and "tech" end up in the same category. If it doesn't exist
+// yet it is created, or errUnknownCategory is returned unless create is set.
+func resolveCategory(tx *gorm.DB, value string, create bool) (*models.Category, error) {
+	name := strings.Join(strings.Fields(value), " ")
+	slug := categorySlug(name)
+
+	var category models.Category
+	find := func() error {
+		return tx.Where("slug = ? OR LOWER(name) = LOWER(?)", slug, name).
+			Order("created_at ASC").
+			First(&category).Error
+	}
+	err := find()
+	if err != gorm.ErrRecordNotFound {
+		return &category, err
+	}
+	if !create {
+		return nil, errUnknownCategory
+	}
+
+	// Another request may create the same category concurrently
+	category = models.Category{ID: uuid.New(), Name: name, Slug: slug}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, find()
+}
+
+// resolvePostCategory finds the category a post request asks for: by
+// category_id when given, otherwise by name or slug. Only editors create
+// categories on the fly; everyone else picks from the existing ones.
+func resolvePostCategory(c *fiber.Ctx, tx *gorm.DB, id *uuid.UUID, value string) (*models.Category, error) {
+	if id == nil {
+		return resolveCategory(tx, value, isEditor(c))
+	}
+	var category models.Category
+	if err := tx.First(&category, "id = ?", *id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errUnknownCategory
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// setPostCategory points the post at the category
+func setPostCategory(post *models.Post, category *models.Category) {
+	post.CategoryID = &category.ID
+	post.Category = category.Name
+}
+
+// categoryDescendantIDs returns the category's ID and those of all its descendants
+func categoryDescendantIDs(tx *gorm.DB, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := tx.Raw(categoryTreeQuery, id).Scan(&ids).Error
+	return ids, err
+}
+
+// MigrateCategories moves the free-text categories of existing posts
+// into the categories table. Posts that already have a CategoryID are skipped.
+func MigrateCategories(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+
+	var names []string
+	if err := db.Unscoped().Model(&models.Post{}).
+		Where("category_id IS NULL AND category <> ''").
+		Distinct().
+		Pluck("category", &names).Error; err != nil {
+		log.Printf("ERROR: Failed to load post categories: %v", err)
+		return
+	}
+
+	for _, name := range names {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			category, err := resolveCategory(tx, name, true)
+			if err != nil {
+				return err
+			}
+			return tx.Unscoped().Model(&models.Post{}).
+				Where("category_id IS NULL AND category = ?", name).
+				UpdateColumns(map[string]interface{}{"category_id": category.ID, "category": category.Name}).Error
+		})
+		if err != nil {
+			log.Printf("ERROR: Failed to migrate category %q: %v", name, err)
+		}
+	}
+	if len(names) > 0 {
+		log.Printf("✓ Migrated %d post categories", len(names))
+	}
+}
+
+// findCategory loads the category from the :id or :slug route parameter.
+// When it returns a nil category the error response has already been sent.
+func findCategory(c *fiber.Ctx) (*models.Category, error) {
+	var category models.Category
+	query := database.DB
+	if id, err := uuid.Parse(c.Params("id")); err == nil {
+		query = query.Where("id = ?", id)
+	} else {
+		query = query.Where("slug = ?", c.Params("slug", c.Params("id")))
+	}
+	if err := query.First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Category not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	return &category, nil
+}
+
+// GetCategories is the handler for GET /api/categories
+// Returns the category tree, or a flat list with ?flat=true, with the number
+// of published posts in each category.
+func GetCategories(c *fiber.Ctx) error {
+	var categories []*models.Category
+	if err := database.DB.Order("sort_order ASC, name ASC").Find(&categories).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve categories", "error": err.Error(),
+		})
+	}
+
+	// Published post counts per category, in one query
+	var counts []struct {
+		CategoryID uuid.UUID
+		Count      int64
+	}
+	database.DB.Model(&models.Post{}).
+		Scopes(listedPosts).
+		Select("category_id, COUNT(*) AS count").
+		Where("category_id IS NOT NULL").
+		Group("category_id").
+		Scan(&counts)
+	byID := make(map[uuid.UUID]*models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	for _, count := range counts {
+		if category, ok := byID[count.CategoryID]; ok {
+			category.PostCount = count.Count
+		}
+	}
+
+	if c.QueryBool("flat", false) {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Categories retrieved successfully",
+			"data":    categories,
+		})
+	}
+
+	// Build the tree; categories keep their sort order among siblings
+	roots := make([]*models.Category, 0)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Categories retrieved successfully",
+		"data":    roots,
+	})
+}
+
+// GetCategory is the handler for GET /api/categories/:slug
+func GetCategory(c *fiber.Ctx) error {
+	category, err := findCategory(c)
+	if category == nil {
+		return err
+	}
+
+	var children []*models.Category
+	database.DB.Where("parent_id = ?", category.ID).Order("sort_order ASC, name ASC").Find(&children)
+	category.Children = children
+	database.DB.Model(&models.Post{}).Scopes(listedPosts).Where("category_id = ?", category.ID).Count(&category.PostCount)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Category retrieved successfully",
+		"data":    category,
+	})
+}
+
+// GetCategoryPosts is the handler for GET /api/categories/:slug/posts
+// Lists published posts in the category and all of its subcategories.
+func GetCategoryPosts(c *fiber.Ctx) error {
+	// 1. Find the category and its descendants
+	category, err := findCategory(c)
+	if category == nil {
+		return err
+	}
+	ids, err := categoryDescendantIDs(database.DB, category.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to load subcategories", "error": err.Error(),
+		})
+	}
+
+	// 2. Parse pagination parameters
+	page, err := parsePageParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters", "error": err.Error(),
+		})
+	}
+
+	// 3. Count and fetch the posts
+	var posts []models.Post
+	var total int64
+	query := database.DB.Model(&models.Post{}).
+		Preload("Author").
+		Preload("Tags").
+		Scopes(listedPosts).
+		Where("category_id IN ?", ids)
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to count posts", "error": err.Error(),
+		})
+	}
+	if !includeContent(c) {
+		query = query.Omit("content", "content_html", "toc")
+	}
+	hasMore, err := paginatePosts(query, page, &posts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve posts", "error": err.Error(),
+		})
+	}
+
+	decoratePosts(c, posts)
+
+	// 4. Return the response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Posts retrieved successfully",
+		"data":    posts,
+		"meta":    pageMeta(posts, total, page, hasMore),
+	})
+}
+
+// applyCategoryRequest copies the request into the category and checks the
+// parent: it must exist and must not be the category itself or one of its
+// descendants. It returns a client error message, or "" when the request is valid.
+func applyCategoryRequest(category *models.Category, req *CategoryRequest) string {
+	if req.ParentID != nil {
+		if *req.ParentID == category.ID {
+			return "A category cannot be its own parent"
+		}
+		var parent models.Category
+		if err := database.DB.First(&parent, *req.ParentID).Error; err != nil {
+			return "Parent category not found"
+		}
+		ids, err := categoryDescendantIDs(database.DB, category.ID)
+		if err != nil {
+			return "Failed to check the category tree"
+		}
+		for _, id := range ids {
+			if id == parent.ID {
+				return "A category cannot be moved under one of its subcategories"
+			}
+		}
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Name
+	}
+	category.Name = strings.Join(strings.Fields(req.Name), " ")
+	category.Slug = categorySlug(slug)
+	category.Description = req.Description
+	category.ParentID = req.ParentID
+	category.SortOrder = req.SortOrder
+	return ""
+}
+
+// parseCategoryRequest parses and validates the body and checks the caller
+// may manage categories. When it returns nil the response has already been sent.
+func parseCategoryRequest(c *fiber.Ctx) (*CategoryRequest, error) {
+	if !isEditor(c) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Editor access required",
+		})
+	}
+	req := new(CategoryRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	return req, nil
+}
+
+// CreateCategory is the handler for POST /api/categories (editors and admins)
+func CreateCategory(c *fiber.Ctx) error {
+	req, err := parseCategoryRequest(c)
+	if req == nil {
+		return err
+	}
+
+	category := models.Category{ID: uuid.New()}
+	if msg := applyCategoryRequest(&category, req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": msg,
+		})
+	}
+	if err := database.DB.Create(&category).Error; err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error", "message": "A category with this name or slug already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to create category", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Category created successfully",
+		"data":    category,
+	})
+}
+
+// UpdateCategory is the handler for PUT /api/categories/:id (editors and admins)
+// Renaming a category also renames it on its posts.
+func UpdateCategory(c *fiber.Ctx) error {
+	req, err := parseCategoryRequest(c)
+	if req == nil {
+		return err
+	}
+	category, err := findCategory(c)
+	if category == nil {
+		return err
+	}
+
+	if msg := applyCategoryRequest(category, req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": msg,
+		})
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Post{}).
+			Where("category_id = ?", category.ID).
+			UpdateColumn("category", category.Name).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error", "message": "A category with this name or slug already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update category", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Category updated successfully",
+		"data":    category,
+	})
+}
+
+// DeleteCategory is the handler for DELETE /api/categories/:id (editors and admins)
+// Only categories without posts can be deleted; their subcategories move up
+// to the deleted category's parent.
+func DeleteCategory(c *fiber.Ctx) error {
+	if !isEditor(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Editor access required",
+		})
+	}
+	category, err := findCategory(c)
+	if category == nil {
+		return err
+	}
+
+	var posts int64
+	database.DB.Unscoped().Model(&models.Post{}).Where("category_id = ?", category.ID).Count(&posts)
+	if posts > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "Category still has posts, move them to another category first",
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Category{}).
+			Where("parent_id = ?", category.ID).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(category).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to delete category", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Category deleted successfully",
+	})
+}
@@ -0,0 +1,537 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateCommentRequest is the struct for parsing and validating a new comment
+type CreateCommentRequest struct {
+	Body     string `json:"body" validate:"required,min=1,max=5000"`
+	ParentID string `json:"parent_id" validate:"omitempty,uuid"` // Set when replying to another comment
+}
+
+// UpdateCommentRequest is the struct for editing a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=5000"`
+}
+
+// ModerateCommentRequest is the struct for the moderation action
+type ModerateCommentRequest struct {
+	Action string `json:"action" validate:"required,oneof=approve reject spam"`
+}
+
+// CommentSettingsRequest is the struct for updating per-post comment settings
+type CommentSettingsRequest struct {
+	CommentsClosed          *bool `json:"comments_closed"`
+	CommentsRequireApproval *bool `json:"comments_require_approval"`
+}
+
+// moderationActions maps a moderation action to the resulting status
+var moderationActions = map[string]string{
+	"approve": models.CommentStatusApproved,
+	"reject":  models.CommentStatusRejected,
+	"spam":    models.CommentStatusSpam,
+}
+
+// findPostByParam loads the post referenced by the :id param
+func findPostByParam(c *fiber.Ctx) (*models.Post, error) {
+	postID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid post ID format", "error": err.Error(),
+		})
+	}
+
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Post not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	return &post, nil
+}
+
+// findComment loads the comment referenced by the :id param
+func findComment(c *fiber.Ctx) (*models.Comment, error) {
+	commentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid comment ID format", "error": err.Error(),
+		})
+	}
+
+	var comment models.Comment
+	if err := database.DB.First(&comment, commentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Comment not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	return &comment, nil
+}
+
+// GetComments is the handler for GET /api/posts/:id/comments
+// Top-level comments are paginated; each one comes with its full reply thread.
+func GetComments(c *fiber.Ctx) error {
+	// 1. Find the post
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canReadPost(c, post) {
+		return postNotFound(c)
+	}
+	if !canReadContent(c, post) {
+		return postLocked(c)
+	}
+
+	// 2. Parse pagination (offset mode only, threads are ordered oldest first)
+	page, err := parsePageParams(c)
+	if err != nil || page.cursorMode() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters",
+		})
+	}
+
+	// Deleted placeholders stay visible so their replies keep a parent
+	visible := []string{models.CommentStatusApproved, models.CommentStatusDeleted}
+
+	// 3. Count and load the top-level comments for this page
+	var total int64
+	roots := []*models.Comment{}
+	query := database.DB.Model(&models.Comment{}).
+		Where("post_id = ? AND parent_id IS NULL AND status IN ?", post.ID, visible)
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to count comments", "error": err.Error(),
+		})
+	}
+	if err := query.Preload("Author").
+		Order("created_at ASC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&roots).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve comments", "error": err.Error(),
+		})
+	}
+
+	// 4. Load every reply of those threads in one query and build the tree
+	if len(roots) > 0 {
+		rootIDs := make([]uuid.UUID, len(roots))
+		byID := make(map[uuid.UUID]*models.Comment, len(roots))
+		for i, root := range roots {
+			rootIDs[i] = root.ID
+			byID[root.ID] = root
+		}
+
+		var replies []*models.Comment
+		if err := database.DB.Preload("Author").
+			Where("root_id IN ? AND status IN ?", rootIDs, visible).
+			Order("created_at ASC").
+			Find(&replies).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to retrieve replies", "error": err.Error(),
+			})
+		}
+		for _, reply := range replies {
+			byID[reply.ID] = reply
+		}
+		for _, reply := range replies {
+			// Replies to hidden (pending, rejected) comments are dropped with them
+			if parent, ok := byID[*reply.ParentID]; ok {
+				parent.Replies = append(parent.Replies, reply)
+			}
+		}
+	}
+
+	// 5. Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Comments retrieved successfully",
+		"data":    roots,
+		"meta": fiber.Map{
+			"total":  total,
+			"limit":  page.Limit,
+			"offset": page.Offset,
+		},
+	})
+}
+
+// CreateComment is the handler for POST /api/posts/:id/comments
+func CreateComment(c *fiber.Ctx) error {
+	// 1. Parse and validate the request body
+	req := new(CreateCommentRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid user data in token",
+		})
+	}
+
+	// 2. The post must be published and open for comments
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if post.Status != "publish" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Post not found",
+		})
+	}
+	if !canReadContent(c, post) {
+		return postLocked(c)
+	}
+	if post.CommentsClosed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Comments are closed for this post",
+		})
+	}
+
+	comment := models.Comment{
+		ID:       uuid.New(),
+		PostID:   post.ID,
+		AuthorID: userID,
+		Body:     req.Body,
+		Status:   models.CommentStatusApproved,
+	}
+
+	// 3. Resolve the thread when replying
+	if req.ParentID != "" {
+		var parent models.Comment
+		err := database.DB.
+			Where("id = ? AND post_id = ? AND status = ?", req.ParentID, post.ID, models.CommentStatusApproved).
+			First(&parent).Error
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Parent comment not found on this post",
+			})
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = parent.RootID
+		if comment.RootID == nil {
+			comment.RootID = &parent.ID
+		}
+	}
+
+	// 4. Hold the comment for moderation if the post requires it.
+	// The post author and admins are trusted.
+	if post.CommentsRequireApproval && !canManagePost(c, post) {
+		comment.Status = models.CommentStatusPending
+	}
+
+	// 5. Save and return the comment
+	if err := database.DB.Create(&comment).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to create comment", "error": err.Error(),
+		})
+	}
+	database.DB.Preload("Author").First(&comment, comment.ID)
+
+	message := "Comment created successfully"
+	if comment.Status == models.CommentStatusPending {
+		message = "Comment submitted and awaiting approval"
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    comment,
+	})
+}
+
+// UpdateComment is the handler for PUT /api/comments/:id
+// Only the comment author may edit, and only within the edit window.
+func UpdateComment(c *fiber.Ctx) error {
+	// 1. Find the comment
+	comment, err := findComment(c)
+	if comment == nil {
+		return err
+	}
+
+	// 2. Authorization and edit window checks
+	userID, _ := currentUserID(c)
+	if comment.AuthorID != userID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to edit this comment",
+		})
+	}
+	if comment.Status == models.CommentStatusDeleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Comment not found",
+		})
+	}
+	window := time.Duration(config.AppConfig.CommentEditWindowMinutes) * time.Minute
+	if time.Since(comment.CreatedAt) > window {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "The edit window for this comment has passed",
+		})
+	}
+
+	// 3. Parse and validate the request body
+	req := new(UpdateCommentRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	// 4. Save the edit
+	now := time.Now()
+	comment.Body = req.Body
+	comment.EditedAt = &now
+	if err := database.DB.Save(comment).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update comment", "error": err.Error(),
+		})
+	}
+	database.DB.Preload("Author").First(comment, comment.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Comment updated successfully",
+		"data":    comment,
+	})
+}
+
+// DeleteComment is the handler for DELETE /api/comments/:id
+// The comment author, the post author and admins may delete a comment.
+func DeleteComment(c *fiber.Ctx) error {
+	// 1. Find the comment and its post
+	comment, err := findComment(c)
+	if comment == nil {
+		return err
+	}
+	var post models.Post
+	if err := database.DB.Unscoped().First(&post, comment.PostID).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+
+	// 2. Authorization Check
+	userID, _ := currentUserID(c)
+	if comment.AuthorID != userID && !canManagePost(c, &post) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to delete this comment",
+		})
+	}
+
+	// 3. Comments with replies become a placeholder so the thread survives,
+	// everything else is soft deleted.
+	var replies int64
+	database.DB.Model(&models.Comment{}).Where("parent_id = ?", comment.ID).Count(&replies)
+
+	if replies > 0 {
+		comment.Body = ""
+		comment.Status = models.CommentStatusDeleted
+		err = database.DB.Save(comment).Error
+	} else {
+		err = database.DB.Delete(comment).Error
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to delete comment", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Comment deleted successfully",
+	})
+}
+
+// GetModerationQueue is the handler for GET /api/comments/moderation
+// Admins see every post; authors see comments on their own posts.
+func GetModerationQueue(c *fiber.Ctx) error {
+	// 1. Parse query parameters
+	page, err := parsePageParams(c)
+	if err != nil || page.cursorMode() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters",
+		})
+	}
+	status := c.Query("status", models.CommentStatusPending)
+	if _, ok := map[string]bool{
+		models.CommentStatusPending:  true,
+		models.CommentStatusRejected: true,
+		models.CommentStatusSpam:     true,
+	}[status]; !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "status must be one of pending, rejected, spam",
+		})
+	}
+
+	// 2. Build the query, scoped to the caller's posts (as author or
+	// co-author) unless admin
+	query := database.DB.Model(&models.Comment{}).Where("comments.status = ?", status)
+	if !isAdmin(c) {
+		userID, _ := currentUserID(c)
+		query = query.Where("post_id IN (?)",
+			database.DB.Model(&models.Post{}).Unscoped().Select("id").
+				Where("author_id = ? OR id IN (?)", userID,
+					database.DB.Model(&models.PostAuthor{}).Select("post_id").Where("user_id = ?", userID)))
+	}
+
+	// 3. Count and load
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to count comments", "error": err.Error(),
+		})
+	}
+	comments := []models.Comment{}
+	if err := query.Preload("Author").
+		Order("created_at ASC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&comments).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve comments", "error": err.Error(),
+		})
+	}
+
+	// 4. Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Moderation queue retrieved successfully",
+		"data":    comments,
+		"meta": fiber.Map{
+			"total":  total,
+			"limit":  page.Limit,
+			"offset": page.Offset,
+		},
+	})
+}
+
+// ModerateComment is the handler for POST /api/comments/:id/moderate
+func ModerateComment(c *fiber.Ctx) error {
+	// 1. Parse and validate the request body
+	req := new(ModerateCommentRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	// 2. Find the comment and check the caller manages its post
+	comment, err := findComment(c)
+	if comment == nil {
+		return err
+	}
+	var post models.Post
+	if err := database.DB.Unscoped().First(&post, comment.PostID).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	if !canManagePost(c, &post) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to moderate this comment",
+		})
+	}
+	if comment.Status == models.CommentStatusDeleted {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "Deleted comments cannot be moderated",
+		})
+	}
+
+	// 3. Apply the new status
+	comment.Status = moderationActions[req.Action]
+	if err := database.DB.Save(comment).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to moderate comment", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Comment moderated successfully",
+		"data":    comment,
+	})
+}
+
+// UpdateCommentSettings is the handler for PUT /api/posts/:id/comment-settings
+func UpdateCommentSettings(c *fiber.Ctx) error {
+	// 1. Find the post and check ownership
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canManagePost(c, post) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to edit this post",
+		})
+	}
+
+	// 2. Parse the request body, only provided settings change
+	req := new(CommentSettingsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if req.CommentsClosed != nil {
+		post.CommentsClosed = *req.CommentsClosed
+	}
+	if req.CommentsRequireApproval != nil {
+		post.CommentsRequireApproval = *req.CommentsRequireApproval
+	}
+
+	// 3. Save the settings
+	if err := savePost(database.DB, post); err != nil {
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, post)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update comment settings", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Comment settings updated successfully",
+		"data": fiber.Map{
+			"comments_closed":           post.CommentsClosed,
+			"comments_require_approval": post.CommentsRequireApproval,
+		},
+	})
+}
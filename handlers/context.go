@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// currentUserID returns the authenticated user's ID stored by the auth middleware.
+// ok is false for anonymous requests or malformed tokens.
+func currentUserID(c *fiber.Ctx) (userID uuid.UUID, ok bool) {
+	userIDString, _ := c.Locals("userID").(string)
+	if userIDString == "" {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// currentUserRole returns the role claim of the authenticated user.
+// Tokens issued before roles existed are treated as plain authors.
+func currentUserRole(c *fiber.Ctx) string {
+	role, _ := c.Locals("userRole").(string)
+	if role == "" {
+		return models.RoleAuthor
+	}
+	return role
+}
+
+// isAdmin reports whether the authenticated user is an admin
+func isAdmin(c *fiber.Ctx) bool {
+	return currentUserRole(c) == models.RoleAdmin
+}
+
+// canManagePost reports whether the authenticated user may moderate
+// things attached to a post (comments, settings): its authors or an admin.
+func canManagePost(c *fiber.Ctx, post *models.Post) bool {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return false
+	}
+	return isAdmin(c) || isPostAuthor(post, userID)
+}
+
+// canReadPost reports whether the caller may read a post. Published posts
+// are public; otherwise only its authors and admins may, plus editors for
+// posts waiting for review.
+func canReadPost(c *fiber.Ctx, post *models.Post) bool {
+	switch {
+	case post.Status == models.PostStatusPublish:
+		return true
+	case canManagePost(c, post):
+		return true
+	case post.Status == models.PostStatusPendingReview:
+		return isEditor(c)
+	}
+	return false
+}
+
+// postNotFound is the response for posts the caller may not read, so
+// unpublished posts can't be told apart from missing ones
+func postNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"status": "error", "message": "Post not found",
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// maxFeaturedPosts caps how many posts the featured carousel returns
+const maxFeaturedPosts = 20
+
+// PinPostRequest is the struct for pinning a post
+type PinPostRequest struct {
+	Until *time.Time `json:"until" validate:"required"` // RFC 3339; the pin expires on its own
+}
+
+// FeaturePostRequest is the struct for featuring a post
+type FeaturePostRequest struct {
+	Rank int `json:"rank" validate:"required,min=1,max=100"` // Lower ranks come first
+}
+
+// pinnedPosts is a GORM scope selecting the posts whose pin hasn't expired
+func pinnedPosts(db *gorm.DB) *gorm.DB {
+	return db.Where("posts.pinned_until > ?", time.Now())
+}
+
+// unpinnedPosts is the complement of pinnedPosts
+func unpinnedPosts(db *gorm.DB) *gorm.DB {
+	return db.Where("posts.pinned_until IS NULL OR posts.pinned_until <= ?", time.Now())
+}
+
+// findCuratedPost loads the post in :id for an admin curation endpoint.
+// Only published posts can be pinned or featured.
+func findCuratedPost(c *fiber.Ctx) (*models.Post, error) {
+	if !isAdmin(c) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Admin access required",
+		})
+	}
+	post, err := findPostByParam(c)
+	if post == nil {
+		return nil, err
+	}
+	if post.Status != models.PostStatusPublish {
+		return nil, c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "Only published posts can be pinned or featured",
+		})
+	}
+	return post, nil
+}
+
+// setCuration writes a curation column without touching the post's
+// version or updated_at: pinning is not an edit of the post
+func setCuration(c *fiber.Ctx, post *models.Post, column string, value interface{}, message string) error {
+	if err := database.DB.Unscoped().Model(post).UpdateColumn(column, value).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update post", "error": err.Error(),
+		})
+	}
+	database.DB.Preload("Author").Preload("Tags").Omit("content", "content_html", "toc").First(post, post.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    post,
+	})
+}
+
+// PinPost is the handler for PUT /api/admin/posts/:id/pin (ADMIN)
+// Pins a published post to the top of GET /api/posts until the given time.
+func PinPost(c *fiber.Ctx) error {
+	post, err := findCuratedPost(c)
+	if post == nil {
+		return err
+	}
+
+	req := new(PinPostRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if !req.Until.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "until must be in the future",
+		})
+	}
+
+	return setCuration(c, post, "pinned_until", *req.Until, "Post pinned successfully")
+}
+
+// UnpinPost is the handler for DELETE /api/admin/posts/:id/pin (ADMIN)
+func UnpinPost(c *fiber.Ctx) error {
+	post, err := findCuratedPost(c)
+	if post == nil {
+		return err
+	}
+	return setCuration(c, post, "pinned_until", nil, "Post unpinned successfully")
+}
+
+// FeaturePost is the handler for PUT /api/admin/posts/:id/feature (ADMIN)
+// Adds a published post to the featured carousel at the given rank.
+func FeaturePost(c *fiber.Ctx) error {
+	post, err := findCuratedPost(c)
+	if post == nil {
+		return err
+	}
+
+	req := new(FeaturePostRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	return setCuration(c, post, "featured_rank", req.Rank, "Post featured successfully")
+}
+
+// UnfeaturePost is the handler for DELETE /api/admin/posts/:id/feature (ADMIN)
+func UnfeaturePost(c *fiber.Ctx) error {
+	post, err := findCuratedPost(c)
+	if post == nil {
+		return err
+	}
+	return setCuration(c, post, "featured_rank", nil, "Post removed from featured successfully")
+}
+
+// GetFeaturedPosts is the handler for GET /api/posts/featured
+// Returns the featured published posts, lowest rank first.
+func GetFeaturedPosts(c *fiber.Ctx) error {
+	posts := []models.Post{}
+	err := database.DB.
+		Preload("Author").
+		Preload("Tags").
+		Scopes(listedPosts).
+		Omit("content", "content_html", "toc").
+		Where("posts.featured_rank IS NOT NULL").
+		Order("posts.featured_rank ASC").
+		Order(postSortKey + " DESC").
+		Limit(maxFeaturedPosts).
+		Find(&posts).Error
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve featured posts", "error": err.Error(),
+		})
+	}
+
+	decoratePosts(c, posts)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Featured posts retrieved successfully",
+		"data":    posts,
+	})
+}
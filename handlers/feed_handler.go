@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const feedItemLimit = 20
+
+// Frontend URLs of the things feeds and sitemaps link to
+func postURL(post *models.Post) string {
+	return config.AppConfig.SiteURL + "/posts/" + post.ID.String()
+}
+
+func categoryURL(slug string) string {
+	return config.AppConfig.SiteURL + "/categories/" + url.PathEscape(slug)
+}
+
+func tagURL(name string) string {
+	return config.AppConfig.SiteURL + "/tags/" + url.PathEscape(name)
+}
+
+func authorURL(authorID uuid.UUID) string {
+	return config.AppConfig.SiteURL + "/authors/" + authorID.String()
+}
+
+// feed is what every feed format is rendered from
+type feed struct {
+	Title       string
+	Description string
+	Link        string // Frontend page the feed belongs to
+	SelfURL     string // The feed's own URL
+	Updated     time.Time
+	Posts       []models.Post
+}
+
+// loadFeed builds the feed for the request. ?category=<slug> (including
+// subcategories), ?tag=<name> and ?author=<id> narrow it down.
+// When it returns a nil feed the error response has already been sent.
+func loadFeed(c *fiber.Ctx) (*feed, error) {
+	f := &feed{
+		Title:       config.AppConfig.SiteTitle,
+		Description: "Latest posts from " + config.AppConfig.SiteTitle,
+		Link:        config.AppConfig.SiteURL,
+		SelfURL:     c.BaseURL() + c.OriginalURL(),
+	}
+	query := database.DB.Model(&models.Post{}).
+		Preload("Author").
+		Preload("Tags").
+		Scopes(listedPosts)
+
+	if slug := c.Query("category"); slug != "" {
+		var category models.Category
+		if err := database.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Category not found",
+			})
+		}
+		ids, err := categoryDescendantIDs(database.DB, category.ID)
+		if err != nil {
+			return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to load subcategories", "error": err.Error(),
+			})
+		}
+		query = query.Where("posts.category_id IN ?", ids)
+		f.Title += " - " + category.Name
+		f.Description = "Latest posts in " + category.Name
+		f.Link = categoryURL(category.Slug)
+	}
+	if name := c.Query("tag"); name != "" {
+		var tag models.Tag
+		if err := database.DB.Where("name = ?", normalizeTagName(name)).First(&tag).Error; err != nil {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Tag not found",
+			})
+		}
+		query = query.Where("posts.id IN (?)", database.DB.Table("post_tags").Select("post_id").Where("tag_id = ?", tag.ID))
+		f.Title += " - #" + tag.Name
+		f.Description = "Latest posts tagged " + tag.Name
+		f.Link = tagURL(tag.Name)
+	}
+	if id := c.Query("author"); id != "" {
+		authorID, err := uuid.Parse(id)
+		var author models.User
+		if err == nil {
+			err = database.DB.First(&author, authorID).Error
+		}
+		if err != nil {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Author not found",
+			})
+		}
+		query = query.Where("posts.author_id = ?", author.ID)
+		f.Title += " - " + author.FullName
+		f.Description = "Latest posts by " + author.FullName
+		f.Link = authorURL(author.ID)
+	}
+
+	if !config.AppConfig.FeedFullContent {
+		query = query.Omit("content", "content_html", "toc")
+	}
+	if err := query.Order(postSortKey + " DESC").Limit(feedItemLimit).Find(&f.Posts).Error; err != nil {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve posts", "error": err.Error(),
+		})
+	}
+	// Feeds are anonymous: protected posts only show their excerpt
+	for i := range f.Posts {
+		if f.Posts[i].Visibility != models.PostVisibilityPublic {
+			lockPost(&f.Posts[i])
+		}
+	}
+
+	for _, post := range f.Posts {
+		if post.UpdatedAt.After(f.Updated) {
+			f.Updated = post.UpdatedAt
+		}
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Unix(0, 0)
+	}
+	return f, nil
+}
+
+// notModified sets Last-Modified and ETag for the feed and reports whether
+// the client's cached copy is still current
+func notModified(c *fiber.Ctx, f *feed, format string) bool {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|%t", format, f.SelfURL, config.AppConfig.FeedFullContent)
+	for _, post := range f.Posts {
+		fmt.Fprintf(hash, "|%s:%d", post.ID, post.Version)
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	lastModified := f.Updated.UTC().Truncate(time.Second)
+
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	// If-None-Match takes precedence over If-Modified-Since
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			if tag = strings.TrimSpace(tag); tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince)); err == nil {
+		return !lastModified.After(since)
+	}
+	return false
+}
+
+// feedBody is the HTML put in feed items: the full post or its excerpt
+func feedBody(post *models.Post) string {
+	if config.AppConfig.FeedFullContent && post.ContentHTML != "" {
+		return post.ContentHTML
+	}
+	return post.Excerpt
+}
+
+// feedDate is when the post was published, or created for older posts
+func feedDate(post *models.Post) time.Time {
+	if post.PublishedAt != nil {
+		return *post.PublishedAt
+	}
+	return post.CreatedAt
+}
+
+// RSS 2.0 documents
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Atom documents
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Author     atomPerson     `xml:"author"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// JSON Feed 1.1 documents
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"` // Excerpt, when the full content isn't published
+	Summary       string           `json:"summary,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func postTagNames(post *models.Post) []string {
+	names := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// sendXML writes an XML document with its declaration
+func sendXML(c *fiber.Ctx, contentType string, doc interface{}) error {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to render feed", "error": err.Error(),
+		})
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(append([]byte(xml.Header), body...))
+}
+
+// GetRSSFeed is the handler for GET /feed.xml (RSS 2.0)
+func GetRSSFeed(c *fiber.Ctx) error {
+	f, err := loadFeed(c)
+	if f == nil {
+		return err
+	}
+	if notModified(c, f, "rss") {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		AtomLink:      atomLink{Href: f.SelfURL, Rel: "self", Type: "application/rss+xml"},
+		Items:         make([]rssItem, 0, len(f.Posts)),
+	}
+	for i := range f.Posts {
+		post := &f.Posts[i]
+		channel.Items = append(channel.Items, rssItem{
+			Title:       post.Title,
+			Link:        postURL(post),
+			GUID:        rssGUID{IsPermaLink: false, Value: post.ID.String()},
+			PubDate:     feedDate(post).UTC().Format(time.RFC1123Z),
+			Creator:     post.Author.FullName,
+			Categories:  append([]string{post.Category}, postTagNames(post)...),
+			Description: feedBody(post),
+		})
+	}
+
+	return sendXML(c, "application/rss+xml; charset=utf-8", rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: channel,
+	})
+}
+
+// GetAtomFeed is the handler for GET /atom.xml
+func GetAtomFeed(c *fiber.Ctx) error {
+	f, err := loadFeed(c)
+	if f == nil {
+		return err
+	}
+	if notModified(c, f, "atom") {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	doc := atomFeed{
+		ID:      f.SelfURL,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, 0, len(f.Posts)),
+	}
+	for i := range f.Posts {
+		post := &f.Posts[i]
+		entry := atomEntry{
+			ID:        "urn:uuid:" + post.ID.String(),
+			Title:     post.Title,
+			Updated:   post.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: feedDate(post).UTC().Format(time.RFC3339),
+			Author:    atomPerson{Name: post.Author.FullName},
+			Link:      atomLink{Href: postURL(post), Rel: "alternate", Type: "text/html"},
+		}
+		entry.Categories = append(entry.Categories, atomCategory{Term: post.Category})
+		for _, name := range postTagNames(post) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: name})
+		}
+		if config.AppConfig.FeedFullContent && post.ContentHTML != "" {
+			entry.Content = &atomText{Type: "html", Value: post.ContentHTML}
+		} else {
+			entry.Summary = &atomText{Type: "text", Value: post.Excerpt}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return sendXML(c, "application/atom+xml; charset=utf-8", doc)
+}
+
+// GetJSONFeed is the handler for GET /feed.json (JSON Feed 1.1)
+func GetJSONFeed(c *fiber.Ctx) error {
+	f, err := loadFeed(c)
+	if f == nil {
+		return err
+	}
+	if notModified(c, f, "json") {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.SelfURL,
+		Description: f.Description,
+		Items:       make([]jsonFeedItem, 0, len(f.Posts)),
+	}
+	for i := range f.Posts {
+		post := &f.Posts[i]
+		item := jsonFeedItem{
+			ID:            post.ID.String(),
+			URL:           postURL(post),
+			Title:         post.Title,
+			Summary:       post.Excerpt,
+			Image:         post.FeaturedImageURL,
+			DatePublished: feedDate(post).UTC().Format(time.RFC3339),
+			DateModified:  post.UpdatedAt.UTC().Format(time.RFC3339),
+			Authors:       []jsonFeedAuthor{{Name: post.Author.FullName, URL: authorURL(post.AuthorID)}},
+			Tags:          postTagNames(post),
+		}
+		if config.AppConfig.FeedFullContent && post.ContentHTML != "" {
+			item.ContentHTML = post.ContentHTML
+		} else {
+			item.ContentText = post.Excerpt
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	return c.JSON(doc, "application/feed+json; charset=utf-8")
+}
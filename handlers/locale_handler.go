@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// localeAll is the ?lang= value that turns off language filtering in listings
+const localeAll = "all"
+
+// LinkTranslationRequest is the struct for linking a post to a translation
+type LinkTranslationRequest struct {
+	PostID uuid.UUID `json:"post_id" validate:"required"`
+}
+
+// isSupportedLocale reports whether posts may be written in the locale
+func isSupportedLocale(locale string) bool {
+	for _, supported := range config.AppConfig.SupportedLocales {
+		if locale == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// requestLocale negotiates the reader's language: ?lang= first, then
+// Accept-Language, falling back to the default locale
+func requestLocale(c *fiber.Ctx) string {
+	if lang := strings.ToLower(c.Query("lang")); lang != "" {
+		if lang == localeAll || isSupportedLocale(lang) {
+			return lang
+		}
+		return config.AppConfig.DefaultLocale
+	}
+	if c.Get(fiber.HeaderAcceptLanguage) == "" {
+		return config.AppConfig.DefaultLocale
+	}
+
+	// The default locale goes first so it wins for "*"
+	offers := []string{config.AppConfig.DefaultLocale}
+	for _, supported := range config.AppConfig.SupportedLocales {
+		if supported != config.AppConfig.DefaultLocale {
+			offers = append(offers, supported)
+		}
+	}
+	if locale := c.AcceptsLanguages(offers...); locale != "" {
+		return locale
+	}
+	return config.AppConfig.DefaultLocale
+}
+
+// setAlternateLinks lists the post's other language versions in Link
+// headers (rel="alternate" with hreflang), so clients can offer a switch
+func setAlternateLinks(c *fiber.Ctx, post *models.Post) {
+	for _, translation := range post.Translations {
+		if translation.PostID == post.ID {
+			continue
+		}
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="alternate"; hreflang="%s"`, translation.URL, translation.Locale))
+	}
+}
+
+// localizedPosts is a GORM scope for listings in a locale: posts written in
+// it, plus default-locale posts that have no published translation in it
+func localizedPosts(locale string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if locale == config.AppConfig.DefaultLocale {
+			return db.Where("posts.locale = ?", locale)
+		}
+		return db.Where(`(posts.locale = ? OR (posts.locale = ? AND NOT EXISTS (
+			SELECT 1 FROM posts t
+			WHERE t.translation_group_id = posts.translation_group_id
+			  AND t.locale = ? AND t.status = 'publish' AND t.visibility <> 'unlisted' AND t.deleted_at IS NULL)))`,
+			locale, config.AppConfig.DefaultLocale, locale)
+	}
+}
+
+// BackfillPostLocales assigns the default locale to posts written before
+// posts had a locale
+func BackfillPostLocales(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	result := db.Unscoped().Model(&models.Post{}).
+		Where("locale = ''").
+		UpdateColumn("locale", config.AppConfig.DefaultLocale)
+	if result.Error != nil {
+		log.Printf("Warning: Failed to backfill post locales: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Assigned the default locale to %d posts", result.RowsAffected)
+	}
+}
+
+// translationTaken reports whether another post in the translation group
+// is already written in the locale
+func translationTaken(groupID *uuid.UUID, locale string, exceptID uuid.UUID) bool {
+	if groupID == nil {
+		return false
+	}
+	var count int64
+	database.DB.Unscoped().Model(&models.Post{}).
+		Where("translation_group_id = ? AND locale = ? AND id <> ?", *groupID, locale, exceptID).
+		Count(&count)
+	return count > 0
+}
+
+// applyPostLocale sets the post's locale from a request; an empty locale
+// keeps the current one. Returns a message when the locale can't be used.
+func applyPostLocale(post *models.Post, locale string) string {
+	locale = strings.ToLower(locale)
+	if locale == "" {
+		locale = post.Locale
+	}
+	if locale == "" {
+		locale = config.AppConfig.DefaultLocale
+	}
+	if !isSupportedLocale(locale) {
+		return "locale must be one of " + strings.Join(config.AppConfig.SupportedLocales, ", ")
+	}
+	if translationTaken(post.TranslationGroupID, locale, post.ID) {
+		return "The post already has a translation in this language"
+	}
+	post.Locale = locale
+	return ""
+}
+
+// findTranslation returns the published translation of the post in the
+// locale, or nil if there is none
+func findTranslation(post *models.Post, locale string) *models.Post {
+	if post.TranslationGroupID == nil || post.Locale == locale {
+		return nil
+	}
+	var translation models.Post
+	err := database.DB.
+		Preload("Author").
+		Preload("Tags").
+		Scopes(publicPosts).
+		Where("posts.translation_group_id = ? AND posts.locale = ?", *post.TranslationGroupID, locale).
+		First(&translation).Error
+	if err != nil {
+		return nil
+	}
+	return &translation
+}
+
+// attachTranslations fills in the published language versions of posts
+// that belong to a translation group
+func attachTranslations(posts []models.Post) {
+	var groupIDs []uuid.UUID
+	for i := range posts {
+		if posts[i].TranslationGroupID != nil {
+			groupIDs = append(groupIDs, *posts[i].TranslationGroupID)
+		}
+	}
+	if len(groupIDs) == 0 {
+		return
+	}
+
+	var versions []models.Post
+	err := database.DB.Model(&models.Post{}).
+		Scopes(publicPosts).
+		Select("posts.id", "posts.title", "posts.locale", "posts.translation_group_id").
+		Where("posts.translation_group_id IN ?", groupIDs).
+		Find(&versions).Error
+	if err != nil {
+		log.Println("Error loading translations:", err)
+		return
+	}
+
+	byGroup := make(map[uuid.UUID][]models.PostTranslation)
+	for i := range versions {
+		group := *versions[i].TranslationGroupID
+		byGroup[group] = append(byGroup[group], models.PostTranslation{
+			Locale: versions[i].Locale,
+			PostID: versions[i].ID,
+			Title:  versions[i].Title,
+			URL:    postURL(&versions[i]),
+		})
+	}
+	for group := range byGroup {
+		translations := byGroup[group]
+		sort.Slice(translations, func(i, j int) bool { return translations[i].Locale < translations[j].Locale })
+	}
+	for i := range posts {
+		if posts[i].TranslationGroupID != nil {
+			posts[i].Translations = byGroup[*posts[i].TranslationGroupID]
+		}
+	}
+}
+
+// findEditablePost loads a post by ID for a translation change and checks
+// the caller may edit it. It responds itself when it returns nil.
+func findEditablePost(c *fiber.Ctx, postID uuid.UUID) (*models.Post, error) {
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, postNotFound(c)
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	if !canEditPost(c, &post) && !isAdmin(c) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to edit this post",
+		})
+	}
+	return &post, nil
+}
+
+// LinkTranslation is the handler for POST /api/posts/:id/translations
+// Makes post_id a translation of the post, leaving its previous group.
+// Both posts must be editable by the caller and in different locales.
+func LinkTranslation(c *fiber.Ctx) error {
+	// 1. Find both posts
+	postID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid post ID format", "error": err.Error(),
+		})
+	}
+	req := new(LinkTranslationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if req.PostID == postID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "A post cannot be its own translation",
+		})
+	}
+	post, err := findEditablePost(c, postID)
+	if post == nil {
+		return err
+	}
+	translation, err := findEditablePost(c, req.PostID)
+	if translation == nil {
+		return err
+	}
+
+	// 2. One post per locale in a group
+	groupID := post.TranslationGroupID
+	if groupID == nil {
+		id := uuid.New()
+		groupID = &id
+	}
+	if translation.Locale == post.Locale || translationTaken(groupID, translation.Locale, translation.ID) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "The post already has a translation in this language",
+		})
+	}
+
+	// 3. Link them. The group is bookkeeping, not an edit, so versions are left alone.
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, p := range []*models.Post{post, translation} {
+			if err := tx.Unscoped().Model(p).UpdateColumn("translation_group_id", *groupID).Error; err != nil {
+				return err
+			}
+			p.TranslationGroupID = groupID
+		}
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error", "message": "The post already has a translation in this language",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to link translation", "error": err.Error(),
+		})
+	}
+
+	posts := []models.Post{*post}
+	attachTranslations(posts)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Translation linked successfully",
+		"data":    fiber.Map{"translation_group_id": groupID, "translations": posts[0].Translations},
+	})
+}
+
+// UnlinkTranslation is the handler for DELETE /api/posts/:id/translations
+// Removes the post from its translation group; the other versions stay linked.
+func UnlinkTranslation(c *fiber.Ctx) error {
+	postID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid post ID format", "error": err.Error(),
+		})
+	}
+	post, err := findEditablePost(c, postID)
+	if post == nil {
+		return err
+	}
+
+	if err := database.DB.Unscoped().Model(post).UpdateColumn("translation_group_id", nil).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to unlink translation", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Translation unlinked successfully",
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequestLocale(t *testing.T) {
+	defaultLocale, supported := config.AppConfig.DefaultLocale, config.AppConfig.SupportedLocales
+	config.AppConfig.DefaultLocale, config.AppConfig.SupportedLocales = "id", []string{"id", "en"}
+	t.Cleanup(func() {
+		config.AppConfig.DefaultLocale, config.AppConfig.SupportedLocales = defaultLocale, supported
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "no preference", want: "id"},
+		{name: "lang parameter", query: "lang=en", want: "en"},
+		{name: "lang is case-insensitive", query: "lang=EN", want: "en"},
+		{name: "all languages", query: "lang=all", want: localeAll},
+		{name: "unsupported lang", query: "lang=fr", want: "id"},
+		{name: "lang wins over Accept-Language", query: "lang=id", acceptLanguage: "en", want: "id"},
+		{name: "Accept-Language", acceptLanguage: "en", want: "en"},
+		{name: "Accept-Language by weight", acceptLanguage: "fr;q=1, en;q=0.8, id;q=0.5", want: "en"},
+		{name: "Accept-Language with a region", acceptLanguage: "en-US,en;q=0.9", want: "en"},
+		{name: "region only", acceptLanguage: "en-GB", want: "en"},
+		{name: "unsupported Accept-Language", acceptLanguage: "fr, de", want: "id"},
+		{name: "wildcard prefers the default", acceptLanguage: "*", want: "id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := map[string]string{}
+			if tt.acceptLanguage != "" {
+				header[fiber.HeaderAcceptLanguage] = tt.acceptLanguage
+			}
+
+			var got string
+			withRequest(t, "/?"+tt.query, header, func(c *fiber.Ctx) {
+				got = requestLocale(c)
+			})
+			if got != tt.want {
+				t.Errorf("requestLocale(?%s, Accept-Language %q) = %q, want %q", tt.query, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// Supported values for the `sort` query parameter
+const (
+	sortLatest     = "latest"
+	sortMostViewed = "most_viewed"
+)
+
+// postSortKey is the column expression used to order post listings.
+// Drafts have no published_at yet, so they fall back to created_at.
+const postSortKey = "COALESCE(published_at, created_at)"
+
+// postCursor is the decoded form of the opaque `after`/`before` cursors.
+type postCursor struct {
+	PublishedAt time.Time `json:"p"`
+	ID          uuid.UUID `json:"i"`
+}
+
+// encode turns the cursor into an opaque, URL-safe string
+func (pc postCursor) encode() string {
+	raw, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodePostCursor parses a cursor produced by postCursor.encode
+func decodePostCursor(s string) (*postCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	pc := new(postCursor)
+	if err := json.Unmarshal(raw, pc); err != nil || pc.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return pc, nil
+}
+
+// cursorFor builds the cursor pointing at the given post
+func cursorFor(post models.Post) postCursor {
+	sortTime := post.CreatedAt
+	if post.PublishedAt != nil {
+		sortTime = *post.PublishedAt
+	}
+	return postCursor{PublishedAt: sortTime, ID: post.ID}
+}
+
+// pageParams holds the pagination options parsed from the query string.
+// When After or Before is set the listing runs in keyset (cursor) mode,
+// otherwise it falls back to the classic limit/offset mode.
+// Cursors encode the (published_at, id) keyset, so they only work with
+// the default "latest" sort.
+type pageParams struct {
+	Limit  int
+	Offset int
+	After  *postCursor
+	Before *postCursor
+	Sort   string
+}
+
+// cursorMode reports whether the request asked for keyset pagination
+func (p pageParams) cursorMode() bool {
+	return p.After != nil || p.Before != nil
+}
+
+// parsePageParams reads limit, offset, after, before and sort from the query string
+func parsePageParams(c *fiber.Ctx) (pageParams, error) {
+	p := pageParams{Limit: defaultPageLimit, Sort: sortLatest}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return p, errors.New("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+		p.Limit = limit
+	}
+
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return p, errors.New("offset must be a non-negative integer")
+		}
+		p.Offset = offset
+	}
+
+	after, before := c.Query("after"), c.Query("before")
+	if after != "" && before != "" {
+		return p, errors.New("after and before cannot be used together")
+	}
+	if after != "" {
+		pc, err := decodePostCursor(after)
+		if err != nil {
+			return p, err
+		}
+		p.After = pc
+	}
+	if before != "" {
+		pc, err := decodePostCursor(before)
+		if err != nil {
+			return p, err
+		}
+		p.Before = pc
+	}
+	if p.cursorMode() && p.Offset != 0 {
+		return p, errors.New("offset cannot be combined with a cursor")
+	}
+
+	switch sort := c.Query("sort", sortLatest); sort {
+	case sortLatest:
+	case sortMostViewed:
+		if p.cursorMode() {
+			return p, errors.New("cursors are only supported with sort=latest")
+		}
+		p.Sort = sort
+	default:
+		return p, errors.New("sort must be one of latest, most_viewed")
+	}
+
+	return p, nil
+}
+
+// paginatePosts applies ordering and the page window to a post query and
+// loads the results. In cursor mode it fetches one extra row to find out
+// whether another page exists in the direction of travel.
+func paginatePosts(query *gorm.DB, p pageParams, posts *[]models.Post) (hasMore bool, err error) {
+	switch {
+	case p.After != nil:
+		query = query.
+			Where("("+postSortKey+", id) < (?, ?)", p.After.PublishedAt, p.After.ID).
+			Order(postSortKey + " DESC").Order("id DESC").
+			Limit(p.Limit + 1)
+	case p.Before != nil:
+		// Walk backwards in ascending order, then flip the page below
+		query = query.
+			Where("("+postSortKey+", id) > (?, ?)", p.Before.PublishedAt, p.Before.ID).
+			Order(postSortKey + " ASC").Order("id ASC").
+			Limit(p.Limit + 1)
+	case p.Sort == sortMostViewed:
+		query = query.
+			Order("view_count DESC").Order(postSortKey + " DESC").Order("id DESC").
+			Limit(p.Limit + 1).
+			Offset(p.Offset)
+	default:
+		query = query.
+			Order(postSortKey + " DESC").Order("id DESC").
+			Limit(p.Limit + 1).
+			Offset(p.Offset)
+	}
+
+	if err := query.Find(posts).Error; err != nil {
+		return false, err
+	}
+
+	if len(*posts) > p.Limit {
+		hasMore = true
+		*posts = (*posts)[:p.Limit]
+	}
+	if p.Before != nil {
+		for i, j := 0, len(*posts)-1; i < j; i, j = i+1, j-1 {
+			(*posts)[i], (*posts)[j] = (*posts)[j], (*posts)[i]
+		}
+	}
+	return hasMore, nil
+}
+
+// pageMeta builds the `meta` object for a post listing response
+func pageMeta(posts []models.Post, total int64, p pageParams, hasMore bool) fiber.Map {
+	meta := fiber.Map{
+		"total":       total,
+		"limit":       p.Limit,
+		"next_cursor": nil,
+		"prev_cursor": nil,
+	}
+	if !p.cursorMode() {
+		meta["offset"] = p.Offset
+		meta["has_more"] = hasMore
+	}
+	if len(posts) == 0 || p.Sort != sortLatest {
+		return meta
+	}
+
+	first, last := cursorFor(posts[0]), cursorFor(posts[len(posts)-1])
+
+	// A next page exists if we saw an extra row going forward, or if we
+	// paged backwards (the page we came from is still ahead of us).
+	if (p.Before == nil && hasMore) || p.Before != nil {
+		meta["next_cursor"] = last.encode()
+	}
+	// A previous page exists if we came from one, or if we saw an extra
+	// row while paging backwards, or if the offset skipped some rows.
+	if p.After != nil || (p.Before != nil && hasMore) || (!p.cursorMode() && p.Offset > 0) {
+		meta["prev_cursor"] = first.encode()
+	}
+	return meta
+}
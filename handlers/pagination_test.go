@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// withRequest runs fn with the context of a GET request to target
+func withRequest(t *testing.T, target string, header map[string]string, fn func(c *fiber.Ctx)) {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		fn(c)
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, target, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("request to %s failed: %v", target, err)
+	}
+}
+
+// useDryRunDB points database.DB at a connection that never reaches a
+// server: queries succeed without returning rows.
+func useDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("opening the dry-run database failed: %v", err)
+	}
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+	return db
+}
+
+func TestPostCursorRoundTrip(t *testing.T) {
+	cursor := postCursor{
+		PublishedAt: time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC),
+		ID:          uuid.New(),
+	}
+
+	decoded, err := decodePostCursor(cursor.encode())
+	if err != nil {
+		t.Fatalf("decodePostCursor(encode()) returned %v", err)
+	}
+	if !decoded.PublishedAt.Equal(cursor.PublishedAt) || decoded.ID != cursor.ID {
+		t.Errorf("decodePostCursor(encode()) = %+v, want %+v", *decoded, cursor)
+	}
+}
+
+func TestDecodePostCursorRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"not json", "bm90IGpzb24"},        // "not json"
+		{"missing id", "eyJwIjoiMjAyNCJ9"}, // {"p":"2024"}
+		{"nil id", "eyJpIjoiMDAwMDAwMDAtMDAwMC0wMDAwLTAwMDAtMDAwMDAwMDAwMDAwIn0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePostCursor(tt.cursor); err == nil {
+				t.Errorf("decodePostCursor(%q) succeeded, want an error", tt.cursor)
+			}
+		})
+	}
+}
+
+func TestParsePageParams(t *testing.T) {
+	cursor := postCursor{PublishedAt: time.Now().UTC(), ID: uuid.New()}.encode()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    pageParams // Compared on Limit, Offset, Sort and which cursor is set
+		after   bool
+		before  bool
+		wantErr bool
+	}{
+		{name: "defaults", query: "", want: pageParams{Limit: defaultPageLimit, Sort: sortLatest}},
+		{name: "limit and offset", query: "limit=5&offset=20", want: pageParams{Limit: 5, Offset: 20, Sort: sortLatest}},
+		{name: "limit is capped", query: "limit=1000", want: pageParams{Limit: maxPageLimit, Sort: sortLatest}},
+		{name: "zero limit", query: "limit=0", wantErr: true},
+		{name: "non-numeric limit", query: "limit=ten", wantErr: true},
+		{name: "negative offset", query: "offset=-1", wantErr: true},
+		{name: "after cursor", query: "after=" + cursor, want: pageParams{Limit: defaultPageLimit, Sort: sortLatest}, after: true},
+		{name: "before cursor", query: "before=" + cursor, want: pageParams{Limit: defaultPageLimit, Sort: sortLatest}, before: true},
+		{name: "after and before", query: "after=" + cursor + "&before=" + cursor, wantErr: true},
+		{name: "invalid cursor", query: "after=garbage", wantErr: true},
+		{name: "cursor with offset", query: "after=" + cursor + "&offset=10", wantErr: true},
+		{name: "most viewed", query: "sort=most_viewed", want: pageParams{Limit: defaultPageLimit, Sort: sortMostViewed}},
+		{name: "most viewed with cursor", query: "sort=most_viewed&after=" + cursor, wantErr: true},
+		{name: "unknown sort", query: "sort=oldest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got pageParams
+			var err error
+			withRequest(t, "/?"+tt.query, nil, func(c *fiber.Ctx) {
+				got, err = parsePageParams(c)
+			})
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePageParams(%q) succeeded, want an error", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePageParams(%q) returned %v", tt.query, err)
+			}
+			if got.Limit != tt.want.Limit || got.Offset != tt.want.Offset || got.Sort != tt.want.Sort {
+				t.Errorf("parsePageParams(%q) = limit %d, offset %d, sort %q; want limit %d, offset %d, sort %q",
+					tt.query, got.Limit, got.Offset, got.Sort, tt.want.Limit, tt.want.Offset, tt.want.Sort)
+			}
+			if (got.After != nil) != tt.after || (got.Before != nil) != tt.before {
+				t.Errorf("parsePageParams(%q) cursors: after %v, before %v; want after %v, before %v",
+					tt.query, got.After != nil, got.Before != nil, tt.after, tt.before)
+			}
+		})
+	}
+}
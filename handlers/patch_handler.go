@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// updatePostFields maps the JSON names of UpdatePostRequest to its Go field
+// names, which is what validator's StructPartial expects.
+var updatePostFields = jsonFieldNames(reflect.TypeOf(UpdatePostRequest{}))
+
+// nullablePostFields may be cleared with `null` in a merge patch.
+// Every other field is required on a post and rejects `null`.
+var nullablePostFields = map[string]bool{
+	"content_format":     true, // Back to the default format
+	"summary":            true,
+	"featured_image_url": true,
+	"tags":               true,
+	"meta_title":         true,
+	"meta_description":   true,
+	"canonical_url":      true,
+	"social_image_url":   true,
+}
+
+// jsonFieldNames maps each field's json tag name to its Go name
+func jsonFieldNames(t reflect.Type) map[string]string {
+	names := make(map[string]string, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = field.Name
+		}
+	}
+	return names
+}
+
+// errNullPatchField is returned for `null` on a field that can't be cleared
+var errNullPatchField = errors.New("cannot be null")
+
+// parsePostPatch checks that a merge patch is a JSON object of known fields
+// where only nullable fields are `null`. It returns the patch and the Go
+// names of the fields to validate, which are the ones that aren't `null`.
+func parsePostPatch(body []byte) (map[string]json.RawMessage, []string, error) {
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, nil, errors.New("the patch must be a JSON object")
+	}
+	provided := make([]string, 0, len(patch))
+	for name, raw := range patch {
+		goName, known := updatePostFields[name]
+		if !known {
+			return nil, nil, fmt.Errorf("unknown field %q", name)
+		}
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		if isNull && !nullablePostFields[name] {
+			return nil, nil, fmt.Errorf("%s %w", name, errNullPatchField)
+		}
+		if !isNull {
+			provided = append(provided, goName)
+		}
+	}
+	return patch, provided, nil
+}
+
+// mergePostPatch copies the patched fields from the decoded request into
+// the post; `null` fields arrive as zero values and clear them. Tags and
+// the category need the database and are applied by the caller. Reports
+// whether the rendered content has to be recomputed.
+func mergePostPatch(post *models.Post, req *UpdatePostRequest, patch map[string]json.RawMessage) (contentChanged bool) {
+	for name := range patch {
+		switch name {
+		case "title":
+			post.Title = req.Title
+		case "content":
+			post.Content = req.Content
+			contentChanged = true
+		case "content_format":
+			post.ContentFormat = req.ContentFormat
+			contentChanged = true
+		case "summary":
+			post.Summary = req.Summary
+			contentChanged = true // The excerpt derives from it
+		case "status":
+			applyPostStatus(post, req.Status)
+		case "featured_image_url":
+			post.FeaturedImageURL = req.FeaturedImageURL
+		case "meta_title":
+			post.MetaTitle = req.MetaTitle
+		case "meta_description":
+			post.MetaDescription = req.MetaDescription
+		case "canonical_url":
+			post.CanonicalURL = req.CanonicalURL
+		case "social_image_url":
+			post.SocialImageURL = req.SocialImageURL
+		}
+	}
+	return contentChanged
+}
+
+// PatchPost is the handler for the PATCH /api/posts/:id endpoint
+// It applies a JSON Merge Patch (RFC 7396): fields absent from the body are
+// left untouched, `null` clears optional fields, and only the provided
+// fields are validated. `tags` replaces the whole tag list when present.
+func PatchPost(c *fiber.Ctx) error {
+	// 1. Get the ID from the URL parameters
+	postID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid post ID format", "error": err.Error(),
+		})
+	}
+
+	// 2. Find the existing post and check the caller may edit it
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Post not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	if !canEditPost(c, &post) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": "You are not authorized to edit this post",
+		})
+	}
+	if ok, err := checkIfMatch(c, &post); !ok {
+		return err
+	}
+
+	// 3. The patch must be a JSON object of known fields
+	patch, provided, err := parsePostPatch(c.Body())
+	if err != nil {
+		message := "Invalid request body"
+		if errors.Is(err, errNullPatchField) {
+			message = "Validation failed"
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": message, "error": err.Error(),
+		})
+	}
+
+	// 4. Decode and validate only the provided fields
+	req := new(UpdatePostRequest)
+	if err := json.Unmarshal(c.Body(), req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if len(provided) > 0 {
+		if err := validate.StructPartial(req, provided...); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Validation failed", "error": err.Error(),
+			})
+		}
+	}
+	// Edits without a status keep the current one, which still has to be
+	// allowed: under review, authors can't change a published post in place.
+	target := post.Status
+	if _, ok := patch["status"]; ok {
+		target = req.Status
+	}
+	if err := checkPostTransition(post.Status, target, actorFor(c, &post)); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Invalid status", "error": err.Error(),
+		})
+	}
+	if _, ok := patch["locale"]; ok {
+		if msg := applyPostLocale(&post, req.Locale); msg != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": msg,
+			})
+		}
+	}
+	_, visibilitySet := patch["visibility"]
+	_, passwordSet := patch["password"]
+	if visibilitySet || passwordSet {
+		if msg := applyPostVisibility(&post, req.Visibility, req.Password); msg != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": msg,
+			})
+		}
+	}
+
+	// 5. Merge the patch into the post inside a transaction
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, ok := patch["tags"]; ok {
+			tags, err := resolveTags(tx, req.Tags) // null or [] clears the tags
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&post).Association("Tags").Replace(tags); err != nil {
+				return err
+			}
+		}
+
+		_, byName := patch["category"]
+		_, byID := patch["category_id"]
+		if byName || byID {
+			category, err := resolvePostCategory(c, tx, req.CategoryID, req.Category)
+			if err != nil {
+				return err
+			}
+			setPostCategory(&post, category)
+		}
+
+		if contentChanged := mergePostPatch(&post, req, patch); contentChanged {
+			if err := prepareContent(&post); err != nil {
+				return err
+			}
+		}
+
+		post.UpdatedAt = time.Now()
+		return savePost(tx, &post)
+	})
+	if err != nil {
+		log.Println("Patch transaction failed:", err)
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, &post)
+		}
+		if errors.Is(err, errUnknownCategory) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update post", "error": err.Error(),
+		})
+	}
+
+	invalidateRelatedPosts()
+
+	// 6. Return the updated post
+	database.DB.Preload("Author").Preload("Tags").First(&post, post.ID)
+	decoratePost(c, &post)
+
+	c.Set(fiber.HeaderETag, postETag(&post))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post updated successfully",
+		"data":    post,
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+)
+
+func TestParsePostPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		provided []string
+		wantErr  string // Substring of the error, empty for success
+		nullErr  bool   // The error is errNullPatchField
+	}{
+		{name: "empty object", body: `{}`, provided: []string{}},
+		{name: "provided fields", body: `{"title": "A title", "tags": ["go"]}`, provided: []string{"Tags", "Title"}},
+		{name: "null clears without validation", body: `{"summary": null, "title": "A title"}`, provided: []string{"Title"}},
+		{name: "null on a required field", body: `{"title": null}`, wantErr: "title cannot be null", nullErr: true},
+		{name: "unknown field", body: `{"author_id": "x"}`, wantErr: `unknown field "author_id"`},
+		{name: "not an object", body: `["title"]`, wantErr: "JSON object"},
+		{name: "null body", body: `null`, wantErr: "JSON object"},
+		{name: "malformed json", body: `{"title": `, wantErr: "JSON object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, provided, err := parsePostPatch([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parsePostPatch(%s) = %v, want an error containing %q", tt.body, err, tt.wantErr)
+				}
+				if got := errors.Is(err, errNullPatchField); got != tt.nullErr {
+					t.Errorf("parsePostPatch(%s): errors.Is(err, errNullPatchField) = %v, want %v", tt.body, got, tt.nullErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePostPatch(%s) returned %v", tt.body, err)
+			}
+			sort.Strings(provided)
+			if strings.Join(provided, ",") != strings.Join(tt.provided, ",") {
+				t.Errorf("parsePostPatch(%s) provided %v, want %v", tt.body, provided, tt.provided)
+			}
+		})
+	}
+}
+
+func TestMergePostPatch(t *testing.T) {
+	original := models.Post{
+		Title:            "Original title",
+		Content:          "Original content",
+		Summary:          "Original summary",
+		FeaturedImageURL: "https://example.com/cover.jpg",
+		MetaTitle:        "Original meta title",
+		Status:           models.PostStatusDraft,
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		want           func(post *models.Post) bool
+		contentChanged bool
+	}{
+		{
+			name: "absent fields are untouched",
+			body: `{"title": "A new title"}`,
+			want: func(p *models.Post) bool {
+				return p.Title == "A new title" && p.Content == original.Content && p.Summary == original.Summary && p.MetaTitle == original.MetaTitle
+			},
+		},
+		{
+			name:           "content is re-rendered",
+			body:           `{"content": "New content"}`,
+			want:           func(p *models.Post) bool { return p.Content == "New content" && p.Title == original.Title },
+			contentChanged: true,
+		},
+		{
+			name: "null clears an optional field",
+			body: `{"summary": null, "featured_image_url": null}`,
+			want: func(p *models.Post) bool {
+				return p.Summary == "" && p.FeaturedImageURL == "" && p.Title == original.Title
+			},
+			contentChanged: true, // The excerpt derives from the summary
+		},
+		{
+			name: "status change does its bookkeeping",
+			body: `{"status": "publish"}`,
+			want: func(p *models.Post) bool { return p.Status == models.PostStatusPublish && p.PublishedAt != nil },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, _, err := parsePostPatch([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("parsePostPatch(%s) returned %v", tt.body, err)
+			}
+			req := new(UpdatePostRequest)
+			if err := json.Unmarshal([]byte(tt.body), req); err != nil {
+				t.Fatalf("decoding %s: %v", tt.body, err)
+			}
+
+			post := original
+			contentChanged := mergePostPatch(&post, req, patch)
+			if !tt.want(&post) {
+				t.Errorf("mergePostPatch(%s) gave %+v", tt.body, post)
+			}
+			if contentChanged != tt.contentChanged {
+				t.Errorf("mergePostPatch(%s) contentChanged = %v, want %v", tt.body, contentChanged, tt.contentChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PostAuthorsRequest is the struct for replacing a post's co-authors.
+// The order of the list is the byline order.
+type PostAuthorsRequest struct {
+	Authors []PostAuthorItem `json:"authors" validate:"max=20,dive"`
+}
+
+// PostAuthorItem is one co-author in a PostAuthorsRequest
+type PostAuthorItem struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+	Role   string    `json:"role" validate:"omitempty,oneof=author contributor editor"` // Defaults to author
+}
+
+// isPostAuthor reports whether the user is the post's primary author or a co-author
+func isPostAuthor(post *models.Post, userID uuid.UUID) bool {
+	if userID == uuid.Nil {
+		return false
+	}
+	if post.AuthorID == userID {
+		return true
+	}
+	var count int64
+	database.DB.Model(&models.PostAuthor{}).
+		Where("post_id = ? AND user_id = ?", post.ID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// coAuthoredPosts returns which of the posts the user co-authors, in one query
+func coAuthoredPosts(userID uuid.UUID, ids []uuid.UUID) map[uuid.UUID]bool {
+	var coAuthored []uuid.UUID
+	database.DB.Model(&models.PostAuthor{}).
+		Where("user_id = ? AND post_id IN ?", userID, ids).
+		Pluck("post_id", &coAuthored)
+
+	result := make(map[uuid.UUID]bool, len(coAuthored))
+	for _, id := range coAuthored {
+		result[id] = true
+	}
+	return result
+}
+
+// GetPostAuthors is the handler for GET /api/posts/:id/authors
+// Returns the byline: the primary author first, then the co-authors.
+func GetPostAuthors(c *fiber.Ctx) error {
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canReadPost(c, post) {
+		return postNotFound(c)
+	}
+
+	database.DB.Preload("Author").First(post, post.ID)
+	posts := []models.Post{*post}
+	attachAuthors(posts, []uuid.UUID{post.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post authors retrieved successfully",
+		"data":    posts[0].Authors,
+	})
+}
+
+// UpdatePostAuthors is the handler for PUT /api/posts/:id/authors
+// Replaces the post's co-authors (primary author or admin only). Every
+// co-author may then edit the post; the primary author stays the byline's first name.
+func UpdatePostAuthors(c *fiber.Ctx) error {
+	// 1. Find the post and check the caller may change its credits
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if userID, _ := currentUserID(c); post.AuthorID != userID && !isAdmin(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Only the post's author can change its co-authors",
+		})
+	}
+	if ok, err := checkIfMatch(c, post); !ok {
+		return err
+	}
+
+	// 2. Parse and validate the request body
+	req := new(PostAuthorsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	coAuthors := make([]models.PostAuthor, 0, len(req.Authors))
+	userIDs := make([]uuid.UUID, 0, len(req.Authors))
+	seen := map[uuid.UUID]bool{}
+	for i, item := range req.Authors {
+		if item.UserID == post.AuthorID || seen[item.UserID] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Each co-author must be listed once and differ from the primary author",
+			})
+		}
+		seen[item.UserID] = true
+		role := item.Role
+		if role == "" {
+			role = models.PostAuthorRoleAuthor
+		}
+		coAuthors = append(coAuthors, models.PostAuthor{
+			PostID:   post.ID,
+			UserID:   item.UserID,
+			Role:     role,
+			Position: i + 1, // The primary author is position 0
+		})
+		userIDs = append(userIDs, item.UserID)
+	}
+
+	var found int64
+	if len(userIDs) > 0 {
+		database.DB.Model(&models.User{}).Where("id IN ?", userIDs).Count(&found)
+	}
+	if found != int64(len(userIDs)) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "One or more users were not found",
+		})
+	}
+
+	// 3. Replace the co-authors and bump the post's version
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostAuthor{}).Error; err != nil {
+			return err
+		}
+		if len(coAuthors) > 0 {
+			if err := tx.Omit("User").Create(&coAuthors).Error; err != nil {
+				return err
+			}
+		}
+		post.UpdatedAt = time.Now()
+		return savePost(tx, post)
+	})
+	if err != nil {
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, post)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update co-authors", "error": err.Error(),
+		})
+	}
+
+	// 4. Return the new byline
+	database.DB.Preload("Author").Preload("Tags").First(post, post.ID)
+	decoratePost(c, post)
+
+	c.Set(fiber.HeaderETag, postETag(post))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Co-authors updated successfully",
+		"data":    post.Authors,
+	})
+}
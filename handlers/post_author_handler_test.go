@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakePostDB answers the dry-run database's queries for one post and its
+// co-authors, and records the SQL of every other query
+type fakePostDB struct {
+	post      models.Post
+	coAuthors map[uuid.UUID]bool
+	queries   []string
+}
+
+func useFakePostDB(t *testing.T, post models.Post, coAuthors ...uuid.UUID) *fakePostDB {
+	t.Helper()
+	fake := &fakePostDB{post: post, coAuthors: map[uuid.UUID]bool{}}
+	for _, id := range coAuthors {
+		fake.coAuthors[id] = true
+	}
+
+	db := useDryRunDB(t)
+	err := db.Callback().Query().After("gorm:query").Register("test:fake_post", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.Post:
+			*dest = fake.post
+			tx.RowsAffected = 1
+		case *int64:
+			if tx.Statement.Table == "post_authors" && len(tx.Statement.Vars) == 2 {
+				userID, _ := tx.Statement.Vars[1].(uuid.UUID)
+				if tx.Statement.Vars[0] == fake.post.ID && fake.coAuthors[userID] {
+					*dest, tx.RowsAffected = 1, 1
+				}
+				return
+			}
+			fake.queries = append(fake.queries, tx.Statement.SQL.String())
+		}
+	})
+	if err != nil {
+		t.Fatalf("registering the query callback failed: %v", err)
+	}
+	// Conditional updates of the post succeed
+	err = db.Callback().Update().After("gorm:update").Register("test:fake_update", func(tx *gorm.DB) {
+		tx.RowsAffected = 1
+	})
+	if err != nil {
+		t.Fatalf("registering the update callback failed: %v", err)
+	}
+	return fake
+}
+
+// postCaller is who calls an endpoint in the authorization tests
+type postCaller struct {
+	name   string
+	userID uuid.UUID // uuid.Nil for anonymous callers
+	role   string
+}
+
+// callAs sends the request to handler on route as the caller and returns the status code
+func callAs(t *testing.T, caller postCaller, method, route, target string, handler fiber.Handler) int {
+	t.Helper()
+	app := fiber.New()
+	app.Add(method, route, func(c *fiber.Ctx) error {
+		if caller.userID != uuid.Nil {
+			c.Locals("userID", caller.userID.String())
+			c.Locals("userRole", caller.role)
+		}
+		return handler(c)
+	})
+	resp, err := app.Test(httptest.NewRequest(method, target, nil))
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, target, err)
+	}
+	return resp.StatusCode
+}
+
+func TestCoAuthorAuthorization(t *testing.T) {
+	post := models.Post{ID: uuid.New(), AuthorID: uuid.New(), Version: 1, Status: models.PostStatusPublish}
+	coAuthorID := uuid.New()
+
+	var (
+		author    = postCaller{name: "author", userID: post.AuthorID, role: models.RoleAuthor}
+		coAuthor  = postCaller{name: "co-author", userID: coAuthorID, role: models.RoleAuthor}
+		stranger  = postCaller{name: "another author", userID: uuid.New(), role: models.RoleAuthor}
+		editor    = postCaller{name: "editor", userID: uuid.New(), role: models.RoleEditor}
+		admin     = postCaller{name: "admin", userID: uuid.New(), role: models.RoleAdmin}
+		anonymous = postCaller{name: "anonymous"}
+	)
+
+	tests := []struct {
+		caller         postCaller
+		canEdit        bool
+		canManage      bool
+		deleteStatus   int  // DELETE /posts/:id
+		purgeForbidden bool // DELETE /posts/:id?permanent=true
+	}{
+		{caller: author, canEdit: true, canManage: true, deleteStatus: fiber.StatusOK},
+		{caller: coAuthor, canEdit: true, canManage: true, deleteStatus: fiber.StatusOK},
+		{caller: stranger, deleteStatus: fiber.StatusForbidden, purgeForbidden: true},
+		{caller: editor, deleteStatus: fiber.StatusForbidden, purgeForbidden: true},
+		{caller: admin, canManage: true, deleteStatus: fiber.StatusForbidden},
+		{caller: anonymous, deleteStatus: fiber.StatusForbidden, purgeForbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caller.name, func(t *testing.T) {
+			useFakePostDB(t, post, coAuthorID)
+
+			var canEdit, canManage bool
+			withRequest(t, "/", nil, func(c *fiber.Ctx) {
+				if tt.caller.userID != uuid.Nil {
+					c.Locals("userID", tt.caller.userID.String())
+					c.Locals("userRole", tt.caller.role)
+				}
+				canEdit, canManage = canEditPost(c, &post), canManagePost(c, &post)
+			})
+			if canEdit != tt.canEdit {
+				t.Errorf("canEditPost = %v, want %v", canEdit, tt.canEdit)
+			}
+			if canManage != tt.canManage {
+				t.Errorf("canManagePost = %v, want %v", canManage, tt.canManage)
+			}
+
+			target := "/posts/" + post.ID.String()
+			if got := callAs(t, tt.caller, fiber.MethodDelete, "/posts/:id", target, DeletePost); got != tt.deleteStatus {
+				t.Errorf("DELETE %s = %d, want %d", target, got, tt.deleteStatus)
+			}
+			got := callAs(t, tt.caller, fiber.MethodDelete, "/posts/:id", target+"?permanent=true", DeletePost)
+			if (got == fiber.StatusForbidden) != tt.purgeForbidden {
+				t.Errorf("DELETE %s?permanent=true = %d, want forbidden: %v", target, got, tt.purgeForbidden)
+			}
+		})
+	}
+}
+
+func TestModerationQueueScope(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		role      string
+		wantScope bool
+	}{
+		{role: models.RoleAuthor, wantScope: true},
+		{role: models.RoleEditor, wantScope: true},
+		{role: models.RoleAdmin, wantScope: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			fake := useFakePostDB(t, models.Post{})
+			caller := postCaller{name: tt.role, userID: userID, role: tt.role}
+			if got := callAs(t, caller, fiber.MethodGet, "/comments/moderation", "/comments/moderation", GetModerationQueue); got != fiber.StatusOK {
+				t.Fatalf("GET /comments/moderation = %d", got)
+			}
+			if len(fake.queries) == 0 {
+				t.Fatal("the moderation queue wasn't counted")
+			}
+
+			count := fake.queries[0]
+			scoped := strings.Contains(count, "author_id =") && strings.Contains(count, "FROM \"post_authors\" WHERE user_id =")
+			if scoped != tt.wantScope {
+				t.Errorf("scoped to authored and co-authored posts: %v, want %v\n%s", scoped, tt.wantScope, count)
+			}
+		})
+	}
+}
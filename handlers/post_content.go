@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+	"github.com/mohamadsolkhannawawi/article-backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// excerptWords is how many words of the rendered text go into an excerpt
+	excerptWords = 40
+	// wordsPerMinute is the average reading speed used for reading time
+	wordsPerMinute = 200
+	// contentRenderVersion is stored with the rendered columns. Bump it
+	// whenever prepareContent's output changes, and posts rendered by an
+	// older version are re-rendered on the next boot.
+	contentRenderVersion = 1
+	// renderBatchSize is how many posts RenderMissingContent loads at a time
+	renderBatchSize = 100
+)
+
+// prepareContent computes everything derived from a post's Content.
+// It runs on every create/update so reads never have to render anything.
+func prepareContent(post *models.Post) error {
+	if post.ContentFormat == "" {
+		post.ContentFormat = models.ContentFormatMarkdown
+	}
+
+	// HTML written by an author is sanitized before it is even stored,
+	// so the raw source handed back to clients is safe as well.
+	if post.ContentFormat == models.ContentFormatHTML {
+		post.Content = utils.SanitizeHTML(post.Content)
+	}
+
+	rendered, err := utils.RenderContent(post.Content, post.ContentFormat)
+	if err != nil {
+		return err
+	}
+	// Headings get anchors so the table of contents can link to them
+	post.ContentHTML, post.TOC = utils.AddHeadingAnchors(rendered)
+
+	// Card metadata, so list endpoints can skip the full content
+	words := strings.Fields(utils.PlainText(rendered))
+	post.WordCount = len(words)
+	post.ReadingTimeMinutes = (post.WordCount + wordsPerMinute - 1) / wordsPerMinute
+	if post.ReadingTimeMinutes < 1 {
+		post.ReadingTimeMinutes = 1
+	}
+
+	post.RenderVersion = contentRenderVersion
+
+	post.Summary = strings.TrimSpace(post.Summary)
+	switch {
+	case post.Summary != "":
+		post.Excerpt = post.Summary
+	case len(words) > excerptWords:
+		post.Excerpt = strings.Join(words[:excerptWords], " ") + "…"
+	default:
+		post.Excerpt = strings.Join(words, " ")
+	}
+	return nil
+}
+
+// attachSource withholds the author's source (Content) from callers who
+// can't edit the post. Markdown may embed raw HTML, so readers only ever
+// get the sanitized content_html.
+func attachSource(c *fiber.Ctx, posts []models.Post) {
+	userID, signedIn := currentUserID(c)
+	withheld := func(post *models.Post) bool {
+		return post.Content != "" && !(signedIn && post.AuthorID == userID)
+	}
+
+	var ids []uuid.UUID
+	for i := range posts {
+		if withheld(&posts[i]) {
+			ids = append(ids, posts[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	coAuthored := map[uuid.UUID]bool{}
+	if signedIn {
+		coAuthored = coAuthoredPosts(userID, ids)
+	}
+	for i := range posts {
+		if withheld(&posts[i]) && !coAuthored[posts[i].ID] {
+			posts[i].Content = ""
+		}
+	}
+}
+
+// includeContent reports whether a list request opted in to full content
+// with `?include=content` (comma-separated, e.g. `include=content,tags`).
+func includeContent(c *fiber.Ctx) bool {
+	for _, field := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(field) == "content" {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderMissingContent fills the cached rendered columns (HTML, excerpt,
+// word count, reading time, table of contents) of posts rendered by an
+// older contentRenderVersion, or never rendered at all. Posts are loaded in
+// batches, and once every post is current it is a single indexed query,
+// so it runs on every boot.
+func RenderMissingContent(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+
+	rendered := 0
+	lastID := uuid.Nil
+	for {
+		var posts []models.Post
+		if err := db.Unscoped().
+			Select("id", "content", "content_format", "summary").
+			Where("render_version < ? AND id > ?", contentRenderVersion, lastID).
+			Order("id ASC").
+			Limit(renderBatchSize).
+			Find(&posts).Error; err != nil {
+			log.Printf("ERROR: Failed to load posts for rendering: %v", err)
+			return
+		}
+
+		for i := range posts {
+			if err := prepareContent(&posts[i]); err != nil {
+				log.Printf("ERROR: Failed to render post %s: %v", posts[i].ID, err)
+				continue
+			}
+			toc, err := json.Marshal(posts[i].TOC)
+			if err != nil {
+				log.Printf("ERROR: Failed to encode the table of contents of post %s: %v", posts[i].ID, err)
+				continue
+			}
+			if err := db.Unscoped().Model(&posts[i]).UpdateColumns(map[string]interface{}{
+				"content":              posts[i].Content,
+				"content_format":       posts[i].ContentFormat,
+				"content_html":         posts[i].ContentHTML,
+				"excerpt":              posts[i].Excerpt,
+				"word_count":           posts[i].WordCount,
+				"reading_time_minutes": posts[i].ReadingTimeMinutes,
+				"toc":                  string(toc),
+				"render_version":       posts[i].RenderVersion,
+			}).Error; err != nil {
+				log.Printf("ERROR: Failed to save the rendered content of post %s: %v", posts[i].ID, err)
+				continue
+			}
+			rendered++
+		}
+
+		// Posts that failed keep their old version and are retried on the next boot
+		if len(posts) < renderBatchSize {
+			break
+		}
+		lastID = posts[len(posts)-1].ID
+	}
+	if rendered > 0 {
+		log.Printf("✓ Rendered content for %d existing posts", rendered)
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// decoratePosts fills the computed, non-column fields of posts
+// (counts and per-user state) with one query per field, never one per post,
+// and withholds the content of posts the caller may not read in full.
+func decoratePosts(c *fiber.Ctx, posts []models.Post) {
+	if len(posts) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+	}
+
+	attachCommentCounts(posts, ids)
+	attachReactions(c, posts, ids)
+	attachBookmarks(c, posts, ids)
+	attachAuthors(posts, ids)
+	attachTranslations(posts)
+	attachAccess(c, posts)
+	attachSource(c, posts)
+}
+
+// decoratePost is decoratePosts for a single post
+func decoratePost(c *fiber.Ctx, post *models.Post) {
+	posts := []models.Post{*post}
+	decoratePosts(c, posts)
+	*post = posts[0]
+}
+
+// attachCommentCounts sets CommentCount to the number of approved comments
+func attachCommentCounts(posts []models.Post, ids []uuid.UUID) {
+	var rows []struct {
+		PostID uuid.UUID
+		Count  int64
+	}
+	err := database.DB.Model(&models.Comment{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("post_id IN ? AND status = ?", ids, models.CommentStatusApproved).
+		Group("post_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Println("Error counting comments:", err)
+		return
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.PostID] = row.Count
+	}
+	for i := range posts {
+		posts[i].CommentCount = counts[posts[i].ID]
+	}
+}
+
+// attachReactions sets the per-type reaction counts and, for an
+// authenticated caller, their own reaction
+func attachReactions(c *fiber.Ctx, posts []models.Post, ids []uuid.UUID) {
+	var rows []struct {
+		PostID uuid.UUID
+		Type   string
+		Count  int64
+	}
+	err := database.DB.Model(&models.PostReaction{}).
+		Select("post_id, type, COUNT(*) AS count").
+		Where("post_id IN ?", ids).
+		Group("post_id, type").
+		Scan(&rows).Error
+	if err != nil {
+		log.Println("Error counting reactions:", err)
+		return
+	}
+
+	counts := make(map[uuid.UUID]map[string]int64)
+	for _, row := range rows {
+		if counts[row.PostID] == nil {
+			counts[row.PostID] = map[string]int64{}
+		}
+		counts[row.PostID][row.Type] = row.Count
+	}
+
+	mine := map[uuid.UUID]string{}
+	if userID, ok := currentUserID(c); ok {
+		var own []models.PostReaction
+		database.DB.Select("post_id, type").Where("post_id IN ? AND user_id = ?", ids, userID).Find(&own)
+		for _, reaction := range own {
+			mine[reaction.PostID] = reaction.Type
+		}
+	}
+
+	for i := range posts {
+		posts[i].Reactions = counts[posts[i].ID]
+		if posts[i].Reactions == nil {
+			posts[i].Reactions = map[string]int64{}
+		}
+		if reaction, ok := mine[posts[i].ID]; ok {
+			posts[i].MyReaction = &reaction
+		}
+	}
+}
+
+// attachBookmarks marks the posts the authenticated caller has bookmarked
+func attachBookmarks(c *fiber.Ctx, posts []models.Post, ids []uuid.UUID) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var bookmarked []uuid.UUID
+	err := database.DB.Model(&models.Bookmark{}).
+		Where("user_id = ? AND post_id IN ?", userID, ids).
+		Pluck("post_id", &bookmarked).Error
+	if err != nil {
+		log.Println("Error loading bookmarks:", err)
+		return
+	}
+
+	set := make(map[uuid.UUID]bool, len(bookmarked))
+	for _, id := range bookmarked {
+		set[id] = true
+	}
+	for i := range posts {
+		posts[i].Bookmarked = set[posts[i].ID]
+	}
+}
+
+// attachAuthors sets the byline: the primary author, then the co-authors in order
+func attachAuthors(posts []models.Post, ids []uuid.UUID) {
+	var coAuthors []models.PostAuthor
+	err := database.DB.Preload("User").
+		Where("post_id IN ?", ids).
+		Order("position ASC, created_at ASC").
+		Find(&coAuthors).Error
+	if err != nil {
+		log.Println("Error loading co-authors:", err)
+		return
+	}
+
+	byPost := make(map[uuid.UUID][]models.PostAuthor)
+	for _, coAuthor := range coAuthors {
+		byPost[coAuthor.PostID] = append(byPost[coAuthor.PostID], coAuthor)
+	}
+	for i := range posts {
+		primary := models.PostAuthor{
+			PostID: posts[i].ID,
+			UserID: posts[i].AuthorID,
+			User:   posts[i].Author,
+			Role:   models.PostAuthorRoleAuthor,
+		}
+		posts[i].Authors = append([]models.PostAuthor{primary}, byPost[posts[i].ID]...)
+	}
+}
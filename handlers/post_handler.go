@@ -1,8 +1,8 @@
 package handlers
 
 import (
+	"errors"
 	"log"
-	"strconv"
 	"strings"
 	"time"
 
@@ -21,12 +21,29 @@ import (
 
 // CreatePostRequest is the struct for parsing and validating the create post request body
 type CreatePostRequest struct {
-	Title            string   `json:"title" validate:"required,min=20"`
-	Content          string   `json:"content" validate:"required,min=200"`
-	Category         string   `json:"category" validate:"required,min=3"`
-	Status           string   `json:"status" validate:"required,oneof=publish draft trash"`
-	FeaturedImageURL string   `json:"featured_image_url" validate:"omitempty,url"` // URL allow empty or valid URL
-	Tags             []string `json:"tags" validate:"omitempty,dive,min=1"`        // "dive" for validating each tag
+	Title            string     `json:"title" validate:"required,min=20"`
+	Content          string     `json:"content" validate:"required,min=200"`
+	ContentFormat    string     `json:"content_format" validate:"omitempty,oneof=markdown html plain"` // Defaults to markdown
+	Summary          string     `json:"summary" validate:"omitempty,max=500"`
+	Category         string     `json:"category" validate:"required_without=CategoryID,omitempty,min=3"` // Name or slug; only editors may name a new one
+	CategoryID       *uuid.UUID `json:"category_id"`                                                     // Takes precedence over category
+	Status           string     `json:"status" validate:"required"`                                      // Checked against the post workflow
+	FeaturedImageURL string     `json:"featured_image_url" validate:"omitempty,url"`                     // URL allow empty or valid URL
+	Tags             []string   `json:"tags" validate:"omitempty,dive,min=1"`                            // "dive" for validating each tag
+
+	// SEO overrides; when empty they are derived from the title, excerpt and featured image
+	MetaTitle       string `json:"meta_title" validate:"omitempty,max=200"`
+	MetaDescription string `json:"meta_description" validate:"omitempty,max=300"`
+	CanonicalURL    string `json:"canonical_url" validate:"omitempty,url"`
+	SocialImageURL  string `json:"social_image_url" validate:"omitempty,url"`
+
+	// Who may read the content; password is set with (and only with) visibility "password"
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public unlisted members password"` // Defaults to public
+	Password   string `json:"password" validate:"omitempty,min=4,max=72"`
+
+	// Language of the post; translation_of links it to the post it translates
+	Locale        string     `json:"locale" validate:"omitempty,max=10"` // Defaults to DEFAULT_LOCALE
+	TranslationOf *uuid.UUID `json:"translation_of"`
 }
 
 // CreatePost is the handler for the POST /api/posts endpoint
@@ -61,40 +78,88 @@ func CreatePost(c *fiber.Ctx) error {
 		})
 	}
 
-	// 3. Logic for handling Tags
-	var tags []*models.Tag // Use slice of pointers to models.Tag
-	// Loop through each tag name sent from the frontend
-	for _, tagName := range req.Tags {
-		var tag models.Tag
-		// Try to find the tag, if not found, create a new one (FirstOrCreate)
-		// This is very efficient and prevents duplicate tags
-		result := database.DB.Where("name = ?", tagName).FirstOrCreate(&tag, models.Tag{
-			ID:   uuid.New(),
-			Name: tagName,
+	// The initial status must be reachable from draft for the author
+	if err := checkPostTransition("", req.Status, workflowActor{Owner: true, Editor: isEditor(c)}); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Invalid status", "error": err.Error(),
 		})
+	}
 
-		if result.Error != nil {
-			log.Println("Error finding/creating tag:", result.Error)
-			// skip tag if error occurs and log the error
-			continue
-		}
-		// Append tag as pointer to slice
-		tags = append(tags, &tag) // Fix here: use &tag to get a pointer
+	// 3. Find or create the tags (names are normalized, so "Go" and "go " are one tag)
+	tags, err := resolveTags(database.DB, req.Tags)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to resolve tags", "error": err.Error(),
+		})
+	}
+
+	// Categories are matched by ID, name or slug; editors create new ones on first use
+	category, err := resolvePostCategory(c, database.DB, req.CategoryID, req.Category)
+	if errors.Is(err, errUnknownCategory) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to resolve category", "error": err.Error(),
+		})
 	}
 
 	// 4. Create new Post instance
 	newPost := models.Post{
 		ID:               uuid.New(),
+		Version:          1,
 		Title:            req.Title,
 		Content:          req.Content,
-		Category:         req.Category,
-		Status:           req.Status,
+		ContentFormat:    req.ContentFormat,
+		Summary:          req.Summary,
 		FeaturedImageURL: req.FeaturedImageURL,
+		MetaTitle:        req.MetaTitle,
+		MetaDescription:  req.MetaDescription,
+		CanonicalURL:     req.CanonicalURL,
+		SocialImageURL:   req.SocialImageURL,
 		AuthorID:         authorID,
 		Tags:             tags, // GORM will automatically fill the 'post_tags' table
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 	}
+	setPostCategory(&newPost, category)
+	applyPostStatus(&newPost, req.Status)
+	if msg := applyPostVisibility(&newPost, req.Visibility, req.Password); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": msg,
+		})
+	}
+
+	// A translation joins the translation group of the post it translates
+	if req.TranslationOf != nil {
+		source, err := findEditablePost(c, *req.TranslationOf)
+		if source == nil {
+			return err
+		}
+		if source.TranslationGroupID == nil {
+			groupID := uuid.New()
+			if err := database.DB.Unscoped().Model(source).UpdateColumn("translation_group_id", groupID).Error; err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"status": "error", "message": "Failed to link translation", "error": err.Error(),
+				})
+			}
+			source.TranslationGroupID = &groupID
+		}
+		newPost.TranslationGroupID = source.TranslationGroupID
+	}
+	if msg := applyPostLocale(&newPost, req.Locale); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": msg,
+		})
+	}
+	// Render and sanitize the content once, at write time
+	if err := prepareContent(&newPost); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Failed to render content", "error": err.Error(),
+		})
+	}
 
 	// 5. Save post to database
 	if err := database.DB.Create(&newPost).Error; err != nil {
@@ -103,12 +168,17 @@ func CreatePost(c *fiber.Ctx) error {
 		})
 	}
 
+	invalidateRelatedPosts()
+
 	// 6. Load Author and Tags relations for response
 	// (By default GORM does not automatically load relations on Create)
 	// We will load them manually to ensure the JSON response is complete.
 	database.DB.Preload("Author").Preload("Tags").First(&newPost, newPost.ID)
 
+	decoratePost(c, &newPost)
+
 	// 7. Return the newly created post
+	c.Set(fiber.HeaderETag, postETag(&newPost))
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Post created successfully",
@@ -118,9 +188,13 @@ func CreatePost(c *fiber.Ctx) error {
 
 // GetPosts is the handler for the GET /api/posts endpoint (REFACTORED)
 func GetPosts(c *fiber.Ctx) error {
-	// 1. Parse query parameters for pagination
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	// 1. Parse query parameters for pagination (offset or cursor mode)
+	page, err := parsePageParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters", "error": err.Error(),
+		})
+	}
 	// We ignore any 'status' query param here. This endpoint is public.
 
 	var posts []models.Post
@@ -128,10 +202,20 @@ func GetPosts(c *fiber.Ctx) error {
 
 	// 2. Build the database query
 	// We hard-code 'status = publish' because this is the public endpoint.
+	// Pinned posts are listed separately, so they are neither paginated nor counted.
 	query := database.DB.Model(&models.Post{}).
 		Preload("Author").
 		Preload("Tags").
-		Where("status = ?", "publish")
+		Scopes(listedPosts, unpinnedPosts)
+
+	// Only posts in the reader's language, or in the default language when
+	// they haven't been translated; ?lang=all lists every language
+	locale := requestLocale(c)
+	if locale != localeAll {
+		query = query.Scopes(localizedPosts(locale))
+		c.Set(fiber.HeaderContentLanguage, locale)
+	}
+	c.Vary(fiber.HeaderAcceptLanguage)
 
 	// 3. Get the total count of *published* posts
 	if err := query.Count(&total).Error; err != nil {
@@ -141,28 +225,49 @@ func GetPosts(c *fiber.Ctx) error {
 	}
 
 	// 4. Apply pagination and order, then find the posts
-	err := query.
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&posts).Error
-
+	// Cards only need the excerpt; full content is opt-in via ?include=content
+	if !includeContent(c) {
+		query = query.Omit("content", "content_html", "toc")
+	}
+	hasMore, err := paginatePosts(query, page, &posts)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error", "message": "Failed to retrieve posts", "error": err.Error(),
 		})
 	}
+	meta := pageMeta(posts, total, page, hasMore)
+
+	// 5. The first page starts with the pinned posts, most recently published first
+	pinned := []models.Post{}
+	if !page.cursorMode() && page.Offset == 0 {
+		pinnedQuery := database.DB.Model(&models.Post{}).
+			Preload("Author").
+			Preload("Tags").
+			Scopes(listedPosts, pinnedPosts).
+			Order(postSortKey + " DESC").Order("id DESC")
+		if locale != localeAll {
+			pinnedQuery = pinnedQuery.Scopes(localizedPosts(locale))
+		}
+		if !includeContent(c) {
+			pinnedQuery = pinnedQuery.Omit("content", "content_html", "toc")
+		}
+		if err := pinnedQuery.Find(&pinned).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to retrieve pinned posts", "error": err.Error(),
+			})
+		}
+		posts = append(pinned, posts...)
+	}
+	meta["pinned"] = len(pinned)
+
+	decoratePosts(c, posts)
 
-	// 5. Return the response
+	// 6. Return the response
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Posts retrieved successfully",
 		"data":    posts,
-		"meta": fiber.Map{
-			"total":  total,
-			"limit":  limit,
-			"offset": offset,
-		},
+		"meta":    meta,
 	})
 }
 
@@ -201,7 +306,73 @@ func GetPostByID(c *fiber.Ctx) error {
 		})
 	}
 
-	// 4. Return the found post
+	// Unpublished posts are only visible to the people working on them
+	if !canReadPost(c, &post) {
+		return postNotFound(c)
+	}
+
+	// Serve the published translation in the reader's language (?lang=,
+	// then Accept-Language), if there is one
+	if c.Query("lang") != "" || c.Get(fiber.HeaderAcceptLanguage) != "" {
+		if translation := findTranslation(&post, requestLocale(c)); translation != nil {
+			post = *translation
+		}
+	}
+	c.Set(fiber.HeaderContentLanguage, post.Locale)
+	c.Vary(fiber.HeaderAcceptLanguage)
+
+	// Count the view. Buffered where a background flusher runs, written
+	// during this request otherwise (see viewTracker).
+	if post.Status == "publish" {
+		recordView(c, &post)
+	}
+
+	// 4. Pick the content representation: "raw", "rendered" or "both".
+	// Only the post's authors get the source, and get both by default.
+	editor := canEditPost(c, &post)
+	representation := "rendered"
+	if editor {
+		representation = "both"
+	}
+	switch representation = c.Query("content", representation); representation {
+	case "raw", "both":
+		if !editor {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status": "error", "message": "Only the post's authors can read its source",
+			})
+		}
+		if representation == "raw" {
+			post.ContentHTML = ""
+		}
+	case "rendered":
+		post.Content = ""
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "content must be one of raw, rendered, both",
+		})
+	}
+
+	// Members-only and password-protected posts come back locked (excerpt
+	// only) unless the caller may read them
+	decoratePost(c, &post)
+	if post.Visibility == models.PostVisibilityMembers || post.Visibility == models.PostVisibilityPassword {
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	}
+
+	post.Series = seriesNavigation(&post)
+	setAlternateLinks(c, &post)
+
+	// 5. The client's copy is still current. Locked copies get no ETag, so
+	// the full post is sent once it has been unlocked.
+	if !post.Locked {
+		etag := postBodyETag(&post)
+		c.Set(fiber.HeaderETag, etag)
+		if match := c.Get(fiber.HeaderIfNoneMatch); match != "" && noneMatch(match, etag) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+	}
+
+	// 6. Return the found post
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Post retrieved successfully",
@@ -212,12 +383,28 @@ func GetPostByID(c *fiber.Ctx) error {
 // UpdatePostRequest is the struct for validating the update post request body
 // It's almost identical to CreatePostRequest
 type UpdatePostRequest struct {
-	Title            string   `json:"title" validate:"required,min=20"`
-	Content          string   `json:"content" validate:"required,min=200"`
-	Category         string   `json:"category" validate:"required,min=3"`
-	Status           string   `json:"status" validate:"required,oneof=publish draft trash"`
-	FeaturedImageURL string   `json:"featured_image_url" validate:"omitempty,url"`
-	Tags             []string `json:"tags" validate:"omitempty,dive,min=1"`
+	Title            string     `json:"title" validate:"required,min=20"`
+	Content          string     `json:"content" validate:"required,min=200"`
+	ContentFormat    string     `json:"content_format" validate:"omitempty,oneof=markdown html plain"` // Keeps the current format if empty
+	Summary          string     `json:"summary" validate:"omitempty,max=500"`
+	Category         string     `json:"category" validate:"required_without=CategoryID,omitempty,min=3"` // Name or slug; only editors may name a new one
+	CategoryID       *uuid.UUID `json:"category_id"`                                                     // Takes precedence over category
+	Status           string     `json:"status" validate:"required"`                                      // Checked against the post workflow
+	FeaturedImageURL string     `json:"featured_image_url" validate:"omitempty,url"`
+	Tags             []string   `json:"tags" validate:"omitempty,dive,min=1"`
+
+	// SEO overrides; when empty they are derived from the title, excerpt and featured image
+	MetaTitle       string `json:"meta_title" validate:"omitempty,max=200"`
+	MetaDescription string `json:"meta_description" validate:"omitempty,max=300"`
+	CanonicalURL    string `json:"canonical_url" validate:"omitempty,url"`
+	SocialImageURL  string `json:"social_image_url" validate:"omitempty,url"`
+
+	// Who may read the content; password is set with (and only with) visibility "password"
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public unlisted members password"` // Keeps the current visibility if empty
+	Password   string `json:"password" validate:"omitempty,min=4,max=72"`
+
+	// Language of the post; keeps the current locale if empty
+	Locale string `json:"locale" validate:"omitempty,max=10"`
 }
 
 // UpdatePost is the handler for the PUT /api/posts/:id endpoint (FIXED)
@@ -244,17 +431,16 @@ func UpdatePost(c *fiber.Ctx) error {
 		})
 	}
 
-	// 3. Get Author ID from middleware
-	authorIDString, _ := c.Locals("userID").(string)
-	authorID, _ := uuid.Parse(authorIDString)
-
-	// Authorization Check: Is the logged-in user the author?
-	if post.AuthorID != authorID {
+	// 3. Authorization Check: Is the logged-in user allowed to edit it?
+	if !canEditPost(c, &post) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"status":  "error",
 			"message": "You are not authorized to edit this post",
 		})
 	}
+	if ok, err := checkIfMatch(c, &post); !ok {
+		return err
+	}
 
 	// 4. Parse and validate the request body
 	req := new(UpdatePostRequest)
@@ -268,19 +454,28 @@ func UpdatePost(c *fiber.Ctx) error {
 			"status": "error", "message": "Validation failed", "error": err.Error(),
 		})
 	}
+	if err := checkPostTransition(post.Status, req.Status, actorFor(c, &post)); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Invalid status", "error": err.Error(),
+		})
+	}
+	if msg := applyPostVisibility(&post, req.Visibility, req.Password); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": msg,
+		})
+	}
+	if msg := applyPostLocale(&post, req.Locale); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": msg,
+		})
+	}
 
 	// 5. Start a database transaction
 	err = database.DB.Transaction(func(tx *gorm.DB) error {
 		// 5a. Handle Tag updates (find or create) INSIDE the transaction
-		var tags []models.Tag
-		for _, tagName := range req.Tags {
-			// Find or create the tag
-			var tag models.Tag
-			// Auto-create tag if it doesn't exist
-			if err := tx.FirstOrCreate(&tag, models.Tag{Name: tagName}).Error; err != nil {
-				return err // Rollback if tag creation fails
-			}
-			tags = append(tags, tag)
+		tags, err := resolveTags(tx, req.Tags)
+		if err != nil {
+			return err // Rollback if tag creation fails
 		}
 
 		// 5b. Replace tag associations INSIDE the transaction
@@ -289,15 +484,31 @@ func UpdatePost(c *fiber.Ctx) error {
 		}
 
 		// 5c. Update the post fields
+		category, err := resolvePostCategory(c, tx, req.CategoryID, req.Category)
+		if err != nil {
+			return err
+		}
+		setPostCategory(&post, category)
 		post.Title = req.Title
 		post.Content = req.Content
-		post.Category = req.Category
-		post.Status = req.Status
+		if req.ContentFormat != "" {
+			post.ContentFormat = req.ContentFormat
+		}
+		post.Summary = req.Summary
 		post.FeaturedImageURL = req.FeaturedImageURL
+		post.MetaTitle = req.MetaTitle
+		post.MetaDescription = req.MetaDescription
+		post.CanonicalURL = req.CanonicalURL
+		post.SocialImageURL = req.SocialImageURL
 		post.UpdatedAt = time.Now()
+		applyPostStatus(&post, req.Status)
+		if err := prepareContent(&post); err != nil {
+			return err // Rollback if rendering fails
+		}
 
-		// 5d. Save the updated post INSIDE the transaction
-		if err := tx.Save(&post).Error; err != nil {
+		// 5d. Save the updated post INSIDE the transaction, unless someone
+		// else saved it first
+		if err := savePost(tx, &post); err != nil {
 			return err // Rollback if post save fails
 		}
 
@@ -308,6 +519,14 @@ func UpdatePost(c *fiber.Ctx) error {
 	// 6. Check if the transaction failed
 	if err != nil {
 		log.Println("Transaction failed:", err)
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, &post)
+		}
+		if errors.Is(err, errUnknownCategory) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": err.Error(),
+			})
+		}
 		if strings.Contains(err.Error(), "SQLSTATE 23503") {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"status": "error", "message": "Failed to update tags, constraint violation.", "error": err.Error(),
@@ -318,10 +537,15 @@ func UpdatePost(c *fiber.Ctx) error {
 		})
 	}
 
+	invalidateRelatedPosts()
+
 	// 7. Preload associations for the response (outside the transaction)
 	database.DB.Preload("Author").Preload("Tags").First(&post, post.ID)
 
+	decoratePost(c, &post)
+
 	// 8. Return the updated post
+	c.Set(fiber.HeaderETag, postETag(&post))
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Post updated successfully",
@@ -355,26 +579,46 @@ func DeletePost(c *fiber.Ctx) error {
 		})
 	}
 
-	// 3. Authorization Check
-	authorIDString, _ := c.Locals("userID").(string)
-	authorID, _ := uuid.Parse(authorIDString)
-	if post.AuthorID != authorID {
+	// 3. Authorization Check: the post's authors may trash it, and only
+	// they or an admin may delete it for good
+	permanent := c.QueryBool("permanent", false)
+	if !canEditPost(c, &post) && !(permanent && isAdmin(c)) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"status":  "error",
 			"message": "You are not authorized to delete this post",
 		})
 	}
+	if ok, err := checkIfMatch(c, &post); !ok {
+		return err
+	}
+
+	// 4a. ?permanent=true removes the post, its relations and its images for good
+	if permanent {
+		if err := permanentlyDeletePost(&post); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to delete post", "error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Post deleted permanently",
+		})
+	}
 
-	// 4. Perform the "Trash" (Status update)
-	// This is the correct logic per your requirements.
-	post.Status = "trash"
+	// 4b. Otherwise perform the "Trash" (Status update)
+	// The previous status is remembered so the post can be restored.
+	applyPostStatus(&post, "trash")
 	post.DeletedAt = gorm.DeletedAt{} // Set deleted_at back to NULL
 
-	if err := database.DB.Save(&post).Error; err != nil {
+	if err := savePost(database.DB, &post); err != nil {
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, &post)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error", "message": "Failed to move post to trash", "error": err.Error(),
 		})
 	}
+	invalidateRelatedPosts()
 
 	// 5. Return success response
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -386,8 +630,12 @@ func DeletePost(c *fiber.Ctx) error {
 // GetAdminPosts is the handler for GET /api/admin/posts (REFACTORED)
 func GetAdminPosts(c *fiber.Ctx) error {
 	// 1. Parse query parameters
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	page, err := parsePageParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters", "error": err.Error(),
+		})
+	}
 	status := c.Query("status", "") // e.g., "published", "draft", "trashed"
 
 	var posts []models.Post
@@ -409,11 +657,13 @@ func GetAdminPosts(c *fiber.Ctx) error {
 			status = "trash"
 		} else if status == "published" {
 			status = "publish"
+		} else if status == "pending" {
+			status = "pending_review"
 		}
 		query = query.Where("status = ?", status)
 	} else {
 		// Default: Get all non-thrashed posts
-		query = query.Where("status IN ?", []string{"publish", "draft"})
+		query = query.Where("status IN ?", []string{"publish", "draft", "pending_review"})
 	}
 
 	// 4. Get the total count
@@ -424,28 +674,25 @@ func GetAdminPosts(c *fiber.Ctx) error {
 	}
 
 	// 5. Apply pagination and order
-	err := query.
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&posts).Error
-
+	// Cards only need the excerpt; full content is opt-in via ?include=content
+	if !includeContent(c) {
+		query = query.Omit("content", "content_html", "toc")
+	}
+	hasMore, err := paginatePosts(query, page, &posts)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error", "message": "Failed to retrieve posts", "error": err.Error(),
 		})
 	}
 
+	decoratePosts(c, posts)
+
 	// 6. Return response
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Admin posts retrieved successfully",
 		"data":    posts,
-		"meta": fiber.Map{
-			"total":  total,
-			"limit":  limit,
-			"offset": offset,
-		},
+		"meta":    pageMeta(posts, total, page, hasMore),
 	})
 }
 
@@ -469,9 +716,13 @@ func GetMyPosts(c *fiber.Ctx) error {
 	}
 
 	// 2. Parse query parameters
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
-	status := c.Query("status", "")       // e.g., "publish", "draft", "trash"
+	page, err := parsePageParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters", "error": err.Error(),
+		})
+	}
+	status := c.Query("status", "")       // e.g., "publish", "draft", "pending_review", "trash"
 	published := c.Query("published", "") // e.g., "true" for published only
 
 	var posts []models.Post
@@ -479,15 +730,18 @@ func GetMyPosts(c *fiber.Ctx) error {
 
 	// 3. Build the query - FILTER BY USER ID
 	query := database.DB.Model(&models.Post{}).
-		Where("author_id = ?", userID). // KEY: Filter by authenticated user
+		Where("author_id = ? OR id IN (?)", userID, // KEY: Filter by authenticated user, as author or co-author
+			database.DB.Model(&models.PostAuthor{}).Select("post_id").Where("user_id = ?", userID)).
 		Unscoped().
 		Preload("Author").
 		Preload("Tags")
 
 	// 4. Apply status filter
 	switch status {
-	case "publish", "draft", "trash":
+	case "publish", "draft", "trash", "pending_review":
 		query = query.Where("status = ?", status)
+	case "pending": // Alias for pending_review
+		query = query.Where("status = ?", "pending_review")
 	case "published": // Alias for publish
 		query = query.Where("status = ?", "publish")
 	case "drafts": // Alias for draft
@@ -500,7 +754,7 @@ func GetMyPosts(c *fiber.Ctx) error {
 			query = query.Where("status = ?", "publish")
 		} else {
 			// Default behavior: get all non-trashed posts
-			query = query.Where("status IN ?", []string{"publish", "draft"})
+			query = query.Where("status IN ?", []string{"publish", "draft", "pending_review"})
 		}
 	}
 
@@ -512,25 +766,31 @@ func GetMyPosts(c *fiber.Ctx) error {
 	}
 
 	// 6. Apply pagination and order
-	if err = query.
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&posts).Error; err != nil {
+	// Cards only need the excerpt; full content is opt-in via ?include=content
+	if !includeContent(c) {
+		query = query.Omit("content", "content_html", "toc")
+	}
+	hasMore, err := paginatePosts(query, page, &posts)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status": "error", "message": "Failed to retrieve posts", "error": err.Error(),
 		})
 	}
 
+	decoratePosts(c, posts)
+
 	// 7. Return response
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Your posts retrieved successfully",
 		"data":    posts,
-		"meta": fiber.Map{
-			"total":  total,
-			"limit":  limit,
-			"offset": offset,
-		},
+		"meta":    pageMeta(posts, total, page, hasMore),
 	})
 }
+
+// canEditPost reports whether the authenticated user may edit a post:
+// its primary author or one of its co-authors
+func canEditPost(c *fiber.Ctx, post *models.Post) bool {
+	userID, ok := currentUserID(c)
+	return ok && isPostAuthor(post, userID)
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"gorm.io/gorm"
+)
+
+// publicPosts is a GORM scope limiting a post query to what anonymous
+// readers may see. Use it everywhere posts are listed publicly so every
+// listing drops unpublished posts the same way.
+func publicPosts(db *gorm.DB) *gorm.DB {
+	return db.Where("posts.status = ?", "publish")
+}
+
+// listedPosts is publicPosts minus unlisted posts. Use it for listings,
+// feeds and sitemaps; unlisted posts stay readable by link.
+func listedPosts(db *gorm.DB) *gorm.DB {
+	return publicPosts(db).Where("posts.visibility <> ?", "unlisted")
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errStalePost is returned when a post changed since it was loaded
+var errStalePost = errors.New("the post was modified by someone else")
+
+// postETag is the entity tag for a post version, checked by If-Match. It
+// names the post as well, so the same version of two posts never matches.
+func postETag(post *models.Post) string {
+	return fmt.Sprintf(`W/"%s.%d"`, post.ID, post.Version)
+}
+
+// postBodyETag is the entity tag of a post as GET /api/posts/:id returns
+// it. Co-authors, series, translations, the category and per-reader fields
+// change without a version bump, so the decorated post is hashed into the
+// tag. It starts with the version tag, so it can be sent back in If-Match.
+func postBodyETag(post *models.Post) string {
+	hashed := *post
+	hashed.ViewCount = 0 // Changes on every read
+	body, _ := json.Marshal(hashed)
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`W/"%s.%d.%s"`, post.ID, post.Version, hex.EncodeToString(sum[:8]))
+}
+
+// opaqueTag strips the weak prefix and quotes from an entity tag
+func opaqueTag(tag string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "W/"), `"`)
+}
+
+// etagMatches reports whether an If-Match header value matches the post's
+// current version. Body tags match by their version part.
+func etagMatches(header string, post *models.Post) bool {
+	current := opaqueTag(postETag(post))
+	for _, tag := range strings.Split(header, ",") {
+		tag = opaqueTag(tag)
+		if tag == "*" || tag == current || strings.HasPrefix(tag, current+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// noneMatch reports whether an If-None-Match header value names the tag,
+// using the weak comparison
+func noneMatch(header, etag string) bool {
+	current := opaqueTag(etag)
+	for _, tag := range strings.Split(header, ",") {
+		if tag = opaqueTag(tag); tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch enforces the If-Match precondition before a post is changed.
+// When it returns false the response (428 or 412) has already been sent.
+func checkIfMatch(c *fiber.Ctx, post *models.Post) (bool, error) {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		if !config.AppConfig.RequireIfMatch {
+			return true, nil
+		}
+		return false, c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+			"status": "error", "message": "If-Match header is required",
+		})
+	}
+	if !etagMatches(header, post) {
+		return false, preconditionFailed(c, post)
+	}
+	return true, nil
+}
+
+// preconditionFailed sends 412 with the post's current version
+func preconditionFailed(c *fiber.Ctx, post *models.Post) error {
+	c.Set(fiber.HeaderETag, postETag(post))
+	return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+		"status":  "error",
+		"message": "The post has been modified, reload it and try again",
+		"data": fiber.Map{
+			"version": post.Version,
+			"etag":    postETag(post),
+		},
+	})
+}
+
+// stalePostResponse answers a save that lost the race with another writer
+func stalePostResponse(c *fiber.Ctx, post *models.Post) error {
+	var current models.Post
+	if err := database.DB.Unscoped().Select("id", "version").First(&current, post.ID).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Post not found",
+		})
+	}
+	return preconditionFailed(c, &current)
+}
+
+// savePost writes the post's columns and bumps its version, but only if the
+// row still has the version the post was loaded with. Otherwise it returns
+// errStalePost and nothing is written. Callers drop the cached related posts
+// once the change is committed.
+func savePost(tx *gorm.DB, post *models.Post) error {
+	loaded := post.Version
+	post.Version++
+	result := tx.Unscoped().Model(post).
+		Where("version = ?", loaded).
+		Select("*").Omit(clause.Associations, "view_count", "pinned_until", "featured_rank"). // Views and curation are written separately
+		Updates(post)
+	if result.Error != nil {
+		post.Version = loaded
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		post.Version = loaded
+		return errStalePost
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// workflowActor describes who is trying to change a post's status
+type workflowActor struct {
+	Owner  bool // The post's author or one of its co-authors
+	Editor bool // An editor or admin
+}
+
+// transitionRule decides whether an actor may perform a status change
+type transitionRule func(a workflowActor) bool
+
+var (
+	ownerOrEditor transitionRule = func(a workflowActor) bool { return a.Owner || a.Editor }
+	ownerOnly     transitionRule = func(a workflowActor) bool { return a.Owner }
+
+	// canPublish lets authors publish their own posts directly unless the
+	// review workflow is enabled, in which case only editors can.
+	canPublish transitionRule = func(a workflowActor) bool {
+		return a.Editor || (a.Owner && !config.AppConfig.ReviewWorkflowEnabled)
+	}
+
+	// canResubmit lets authors send changes to a published post back
+	// through review, which is how they edit it under the review workflow.
+	canResubmit transitionRule = func(a workflowActor) bool {
+		return a.Owner && config.AppConfig.ReviewWorkflowEnabled
+	}
+)
+
+// postTransitions is the post status state machine: from -> to -> rule.
+// Every status change, whichever endpoint it comes from, goes through here.
+var postTransitions = map[string]map[string]transitionRule{
+	models.PostStatusDraft: {
+		models.PostStatusDraft:         ownerOrEditor,
+		models.PostStatusPendingReview: ownerOnly,
+		models.PostStatusPublish:       canPublish,
+		models.PostStatusTrash:         ownerOrEditor,
+	},
+	models.PostStatusPendingReview: {
+		models.PostStatusPendingReview: ownerOrEditor,
+		models.PostStatusDraft:         ownerOrEditor, // Withdrawn by the author, or changes requested by an editor
+		models.PostStatusPublish:       canPublish,    // Approved
+		models.PostStatusTrash:         ownerOrEditor,
+	},
+	models.PostStatusPublish: {
+		models.PostStatusPublish:       canPublish,    // Edited in place
+		models.PostStatusPendingReview: canResubmit,   // Edited under review
+		models.PostStatusDraft:         ownerOrEditor, // Unpublish
+		models.PostStatusTrash:         ownerOrEditor,
+	},
+	models.PostStatusTrash: {
+		models.PostStatusTrash:         ownerOrEditor,
+		models.PostStatusDraft:         ownerOrEditor,
+		models.PostStatusPendingReview: ownerOrEditor,
+		models.PostStatusPublish:       canPublish,
+	},
+}
+
+// isEditor reports whether the authenticated user may review other authors' posts
+func isEditor(c *fiber.Ctx) bool {
+	role := currentUserRole(c)
+	return role == models.RoleEditor || role == models.RoleAdmin
+}
+
+// actorFor builds the workflow actor for the caller and a post
+func actorFor(c *fiber.Ctx, post *models.Post) workflowActor {
+	userID, _ := currentUserID(c)
+	return workflowActor{Owner: isPostAuthor(post, userID), Editor: isEditor(c)}
+}
+
+// checkPostTransition returns an error if the actor may not move a post
+// from one status to another. New posts start from draft.
+func checkPostTransition(from, to string, actor workflowActor) error {
+	if from == "" {
+		from = models.PostStatusDraft
+	}
+	rules, ok := postTransitions[from]
+	if !ok {
+		return fmt.Errorf("unknown current status %q", from)
+	}
+	rule, ok := rules[to]
+	if !ok {
+		if _, known := postTransitions[to]; !known {
+			return fmt.Errorf("unknown status %q", to)
+		}
+		return fmt.Errorf("a post cannot move from %s to %s", from, to)
+	}
+	if !rule(actor) {
+		if to == models.PostStatusPublish && actor.Owner {
+			if from == models.PostStatusPublish {
+				return fmt.Errorf("changes to published posts must be submitted for review (status pending_review)")
+			}
+			return fmt.Errorf("posts must be approved by an editor before they are published")
+		}
+		return fmt.Errorf("you are not allowed to move this post from %s to %s", from, to)
+	}
+	return nil
+}
+
+// applyPostStatus sets the new status and the bookkeeping that goes with it
+func applyPostStatus(post *models.Post, status string) {
+	if status == models.PostStatusTrash && post.Status != models.PostStatusTrash {
+		// Remember where the post came from so it can be restored
+		now := time.Now()
+		post.PreviousStatus = post.Status
+		post.TrashedAt = &now
+	} else if status != models.PostStatusTrash {
+		post.PreviousStatus = ""
+		post.TrashedAt = nil
+	}
+
+	post.Status = status
+	if status == models.PostStatusPublish && post.PublishedAt == nil {
+		now := time.Now()
+		post.PublishedAt = &now
+	}
+}
+
+// ReviewRequest is the struct for review actions that carry a comment
+type ReviewRequest struct {
+	Comment string `json:"comment" validate:"max=5000"`
+}
+
+// reviewPost runs one review step: it checks the transition, changes the
+// status and records the step in the review history, all in one transaction.
+func reviewPost(c *fiber.Ctx, action, to string, commentRequired bool) error {
+	// 1. Parse the optional comment
+	req := new(ReviewRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Invalid request body", "error": err.Error(),
+			})
+		}
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if commentRequired && req.Comment == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "A comment is required for this action",
+		})
+	}
+
+	// 2. Find the post
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+
+	// 3. Each action only makes sense from one state
+	actor := actorFor(c, post)
+	var expected string
+	switch action {
+	case models.ReviewActionSubmit:
+		expected = models.PostStatusDraft
+		actor.Editor = false // Submitting is the author's move
+	default:
+		expected = models.PostStatusPendingReview
+		actor.Owner = false // Reviewing is the editor's move, even on their own post
+	}
+	if post.Status != expected {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": fmt.Sprintf("Only %s posts can be processed by this action", expected),
+		})
+	}
+	if err := checkPostTransition(post.Status, to, actor); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": err.Error(),
+		})
+	}
+
+	// 4. Change the status and record the step
+	reviewerID, _ := currentUserID(c)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		applyPostStatus(post, to)
+		post.UpdatedAt = time.Now()
+		if err := savePost(tx, post); err != nil {
+			return err
+		}
+		return tx.Create(&models.PostReview{
+			ID:         uuid.New(),
+			PostID:     post.ID,
+			ReviewerID: reviewerID,
+			Action:     action,
+			Comment:    req.Comment,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, post)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update post status", "error": err.Error(),
+		})
+	}
+	invalidateRelatedPosts()
+
+	database.DB.Preload("Author").Preload("Tags").First(post, post.ID)
+	decoratePost(c, post)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post status updated successfully",
+		"data":    post,
+	})
+}
+
+// SubmitPostForReview is the handler for POST /api/posts/:id/submit
+func SubmitPostForReview(c *fiber.Ctx) error {
+	return reviewPost(c, models.ReviewActionSubmit, models.PostStatusPendingReview, false)
+}
+
+// ApprovePost is the handler for POST /api/posts/:id/approve (editors only)
+func ApprovePost(c *fiber.Ctx) error {
+	return reviewPost(c, models.ReviewActionApprove, models.PostStatusPublish, false)
+}
+
+// RequestPostChanges is the handler for POST /api/posts/:id/request-changes (editors only)
+// The post goes back to draft with the editor's comment.
+func RequestPostChanges(c *fiber.Ctx) error {
+	return reviewPost(c, models.ReviewActionRequestChanges, models.PostStatusDraft, true)
+}
+
+// GetPostReviews is the handler for GET /api/posts/:id/reviews
+// Returns the review history to the post author and editors.
+func GetPostReviews(c *fiber.Ctx) error {
+	// 1. Find the post and check access
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if actor := actorFor(c, post); !actor.Owner && !actor.Editor {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to view this post's reviews",
+		})
+	}
+
+	// 2. Load the history, oldest first
+	reviews := []models.PostReview{}
+	if err := database.DB.Preload("Reviewer").
+		Where("post_id = ?", post.ID).
+		Order("created_at ASC").
+		Find(&reviews).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve reviews", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post reviews retrieved successfully",
+		"data":    reviews,
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+)
+
+func TestCheckPostTransition(t *testing.T) {
+	var (
+		owner    = workflowActor{Owner: true}
+		editor   = workflowActor{Editor: true}
+		stranger = workflowActor{}
+	)
+
+	tests := []struct {
+		name     string
+		from, to string
+		actor    workflowActor
+		review   bool // REVIEW_WORKFLOW_ENABLED
+		wantErr  bool
+	}{
+		{name: "new post starts as draft", from: "", to: models.PostStatusDraft, actor: owner},
+		{name: "owner publishes directly", from: models.PostStatusDraft, to: models.PostStatusPublish, actor: owner},
+		{name: "owner can't publish under review", from: models.PostStatusDraft, to: models.PostStatusPublish, actor: owner, review: true, wantErr: true},
+		{name: "new post can't skip review", from: "", to: models.PostStatusPublish, actor: owner, review: true, wantErr: true},
+		{name: "owner submits for review", from: models.PostStatusDraft, to: models.PostStatusPendingReview, actor: owner, review: true},
+		{name: "editor can't submit for the owner", from: models.PostStatusDraft, to: models.PostStatusPendingReview, actor: editor, wantErr: true},
+		{name: "editor approves", from: models.PostStatusPendingReview, to: models.PostStatusPublish, actor: editor, review: true},
+		{name: "editor requests changes", from: models.PostStatusPendingReview, to: models.PostStatusDraft, actor: editor, review: true},
+		{name: "owner withdraws", from: models.PostStatusPendingReview, to: models.PostStatusDraft, actor: owner, review: true},
+		{name: "owner unpublishes", from: models.PostStatusPublish, to: models.PostStatusDraft, actor: owner},
+		{name: "published post can't go back to review", from: models.PostStatusPublish, to: models.PostStatusPendingReview, actor: owner, wantErr: true},
+		{name: "owner edits a published post", from: models.PostStatusPublish, to: models.PostStatusPublish, actor: owner},
+		{name: "owner can't edit a published post under review", from: models.PostStatusPublish, to: models.PostStatusPublish, actor: owner, review: true, wantErr: true},
+		{name: "owner resubmits a published post under review", from: models.PostStatusPublish, to: models.PostStatusPendingReview, actor: owner, review: true},
+		{name: "editor edits a published post under review", from: models.PostStatusPublish, to: models.PostStatusPublish, actor: editor, review: true},
+		{name: "editor can't resubmit for the owner", from: models.PostStatusPublish, to: models.PostStatusPendingReview, actor: editor, review: true, wantErr: true},
+		{name: "owner trashes", from: models.PostStatusPublish, to: models.PostStatusTrash, actor: owner},
+		{name: "restore to publish needs review", from: models.PostStatusTrash, to: models.PostStatusPublish, actor: owner, review: true, wantErr: true},
+		{name: "restore to draft", from: models.PostStatusTrash, to: models.PostStatusDraft, actor: owner, review: true},
+		{name: "stranger can't touch the post", from: models.PostStatusDraft, to: models.PostStatusTrash, actor: stranger, wantErr: true},
+		{name: "stranger can't publish", from: models.PostStatusDraft, to: models.PostStatusPublish, actor: stranger, wantErr: true},
+		{name: "unknown target status", from: models.PostStatusDraft, to: "archived", actor: editor, wantErr: true},
+		{name: "unknown current status", from: "archived", to: models.PostStatusDraft, actor: editor, wantErr: true},
+	}
+
+	enabled := config.AppConfig.ReviewWorkflowEnabled
+	t.Cleanup(func() { config.AppConfig.ReviewWorkflowEnabled = enabled })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.ReviewWorkflowEnabled = tt.review
+			err := checkPostTransition(tt.from, tt.to, tt.actor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkPostTransition(%q, %q, %+v) = %v, want error: %v", tt.from, tt.to, tt.actor, err, tt.wantErr)
+			}
+		})
+	}
+}
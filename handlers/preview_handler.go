@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	previewAudience          = "post-preview"
+	previewPostKey           = "previewPostID" // Locals key of the post a preview link was opened for
+	defaultPreviewValidHours = 72
+	maxPreviewValidHours     = 24 * 30
+)
+
+// PreviewLinkRequest is the struct for creating a preview link
+type PreviewLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours" validate:"omitempty,min=1,max=720"` // Defaults to 72
+}
+
+// previewKey signs preview tokens. It is derived from the JWT secret so a
+// preview token can never be used as a login token, or the other way round.
+func previewKey() []byte {
+	sum := sha256.Sum256([]byte(config.AppConfig.JWTSecret + "|" + previewAudience))
+	return sum[:]
+}
+
+// CreatePreviewLink is the handler for POST /api/posts/:id/preview-link
+// Issues an expiring signed link that lets anyone holding it read the
+// post as it is, without an account.
+func CreatePreviewLink(c *fiber.Ctx) error {
+	// 1. Find the post and check the caller works on it
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canManagePost(c, post) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to share this post",
+		})
+	}
+	if post.Status == models.PostStatusTrash {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "Trashed posts cannot be previewed",
+		})
+	}
+
+	// 2. Parse the validity period
+	req := new(PreviewLinkRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Invalid request body", "error": err.Error(),
+			})
+		}
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	hours := req.ExpiresInHours
+	if hours == 0 {
+		hours = defaultPreviewValidHours
+	}
+	expiresAt := time.Now().Add(time.Duration(hours) * time.Hour)
+
+	// 3. Sign the token
+	issuerID, _ := currentUserID(c)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   post.ID.String(),
+		Issuer:    issuerID.String(),
+		Audience:  jwt.ClaimStrings{previewAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}).SignedString(previewKey())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to create preview link", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Preview link created successfully",
+		"data": fiber.Map{
+			"token":      token,
+			"url":        config.AppConfig.SiteURL + "/preview/" + token,
+			"api_url":    c.BaseURL() + "/api/preview/" + token,
+			"expires_at": expiresAt,
+		},
+	})
+}
+
+// parsePreviewToken verifies a preview token and returns the ID of the post
+// it was issued for, with its claims
+func parsePreviewToken(token string) (uuid.UUID, *jwt.RegisteredClaims, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return previewKey(), nil
+	}, jwt.WithAudience(previewAudience), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	postID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return postID, claims, nil
+}
+
+// decoratePreview decorates a previewed post like every other read. The
+// link lets its holder read the post whatever its visibility, but the
+// author's source still only goes to the post's authors.
+func decoratePreview(c *fiber.Ctx, post *models.Post) {
+	c.Locals(previewPostKey, post.ID)
+	decoratePost(c, post)
+}
+
+// GetPostPreview is the handler for GET /api/preview/:token
+// Returns the post a preview link was issued for, whatever its status
+// (except trash), as long as the link hasn't expired.
+func GetPostPreview(c *fiber.Ctx) error {
+	// 1. Verify the token
+	postID, claims, err := parsePreviewToken(c.Params("token"))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid or expired preview link",
+		})
+	}
+
+	// 2. Load the post as it is now
+	var post models.Post
+	if err := database.DB.Preload("Author").Preload("Tags").First(&post, postID).Error; err != nil || post.Status == models.PostStatusTrash {
+		return postNotFound(c)
+	}
+	decoratePreview(c, &post)
+
+	// Previews must not be cached by shared caches or indexed
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set("X-Robots-Tag", "noindex, nofollow")
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post preview retrieved successfully",
+		"data":    post,
+		"meta":    fiber.Map{"expires_at": claims.ExpiresAt.Time},
+	})
+}
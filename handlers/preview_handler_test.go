@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestParsePreviewToken(t *testing.T) {
+	secret := config.AppConfig.JWTSecret
+	config.AppConfig.JWTSecret = "test-secret"
+	t.Cleanup(func() { config.AppConfig.JWTSecret = secret })
+
+	postID := uuid.New()
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("signing the token failed: %v", err)
+		}
+		return token
+	}
+	claims := func(audience string, expiresAt time.Time) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Subject:   postID.String(),
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		}
+	}
+	later := time.Now().Add(time.Hour)
+
+	noExpiry := claims(previewAudience, later)
+	noExpiry.ExpiresAt = nil
+	badSubject := claims(previewAudience, later)
+	badSubject.Subject = "not-a-uuid"
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: sign(jwt.SigningMethodHS256, previewKey(), claims(previewAudience, later))},
+		{name: "expired", token: sign(jwt.SigningMethodHS256, previewKey(), claims(previewAudience, time.Now().Add(-time.Minute))), wantErr: true},
+		{name: "no expiry", token: sign(jwt.SigningMethodHS256, previewKey(), noExpiry), wantErr: true},
+		{name: "another audience", token: sign(jwt.SigningMethodHS256, previewKey(), claims(postAccessAudience, later)), wantErr: true},
+		{name: "a post access token", token: sign(jwt.SigningMethodHS256, postAccessKey(), claims(postAccessAudience, later)), wantErr: true},
+		{name: "signed with the login key", token: sign(jwt.SigningMethodHS256, []byte(config.AppConfig.JWTSecret), claims(previewAudience, later)), wantErr: true},
+		{name: "unsigned", token: sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(previewAudience, later)), wantErr: true},
+		{name: "subject is not a post ID", token: sign(jwt.SigningMethodHS256, previewKey(), badSubject), wantErr: true},
+		{name: "garbage", token: "not.a.token", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := parsePreviewToken(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePreviewToken accepted the token for post %s", got)
+				}
+				return
+			}
+			if err != nil || got != postID {
+				t.Errorf("parsePreviewToken = %s, %v, want %s", got, err, postID)
+			}
+		})
+	}
+}
+
+func TestDecoratePreview(t *testing.T) {
+	useDryRunDB(t)
+
+	post := models.Post{
+		ID:          uuid.New(),
+		AuthorID:    uuid.New(),
+		Content:     "# Draft\n\nNot ready yet.",
+		ContentHTML: `<h1 id="draft">Draft</h1><p>Not ready yet.</p>`,
+		Status:      models.PostStatusDraft,
+		Visibility:  models.PostVisibilityMembers,
+	}
+	withRequest(t, "/", nil, func(c *fiber.Ctx) {
+		decoratePreview(c, &post)
+	})
+
+	if post.Locked {
+		t.Error("an anonymous preview locked the post")
+	}
+	if post.Content != "" {
+		t.Errorf("an anonymous preview got the source %q", post.Content)
+	}
+	if post.ContentHTML == "" {
+		t.Error("an anonymous preview lost content_html")
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReactionRequest is the struct for toggling a reaction on a post
+type ReactionRequest struct {
+	Type string `json:"type" validate:"required"`
+}
+
+// reactionTypes returns every reaction type readers may use
+func reactionTypes() []string {
+	return append([]string{models.ReactionLike}, config.AppConfig.ReactionEmojis...)
+}
+
+// isReactionType reports whether t is one of the configured reaction types
+func isReactionType(t string) bool {
+	for _, allowed := range reactionTypes() {
+		if t == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleReaction is the handler for POST /api/posts/:id/reactions
+// Sending the caller's current reaction removes it, sending another type replaces it.
+func ToggleReaction(c *fiber.Ctx) error {
+	// 1. Parse and validate the request body
+	req := new(ReactionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if !isReactionType(req.Type) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Unsupported reaction type", "allowed": reactionTypes(),
+		})
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid user data in token",
+		})
+	}
+
+	// 2. Only published posts can be reacted to
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if post.Status != "publish" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Post not found",
+		})
+	}
+	if !canReadContent(c, post) {
+		return postLocked(c)
+	}
+
+	// 3. Toggle the reaction. A concurrent click may insert the row between
+	// the lookup and the insert; the upsert then sets its type instead of
+	// failing on the unique (post_id, user_id) index.
+	var myReaction *string
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.PostReaction
+		err := tx.Where("post_id = ? AND user_id = ?", post.ID, userID).First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			myReaction = &req.Type
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "post_id"}, {Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"type", "updated_at"}),
+			}).Create(&models.PostReaction{
+				ID: uuid.New(), PostID: post.ID, UserID: userID, Type: req.Type,
+			}).Error
+		case err != nil:
+			return err
+		case existing.Type == req.Type:
+			return tx.Delete(&existing).Error
+		default:
+			myReaction = &req.Type
+			return tx.Model(&existing).Update("type", req.Type).Error
+		}
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update reaction", "error": err.Error(),
+		})
+	}
+
+	// 4. Return the fresh counts
+	decoratePost(c, post)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reaction updated successfully",
+		"data": fiber.Map{
+			"reactions":   post.Reactions,
+			"my_reaction": myReaction,
+		},
+	})
+}
+
+// GetReactions is the handler for GET /api/posts/:id/reactions
+// Returns aggregated counts plus a paginated list of who reacted (optionally ?type=).
+func GetReactions(c *fiber.Ctx) error {
+	// 1. Find the post
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canReadPost(c, post) {
+		return postNotFound(c)
+	}
+	if !canReadContent(c, post) {
+		return postLocked(c)
+	}
+
+	page, err := parsePageParams(c)
+	if err != nil || page.cursorMode() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters",
+		})
+	}
+
+	// 2. List the reactions, newest first
+	query := database.DB.Model(&models.PostReaction{}).Where("post_id = ?", post.ID)
+	if reactionType := c.Query("type"); reactionType != "" {
+		query = query.Where("type = ?", reactionType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to count reactions", "error": err.Error(),
+		})
+	}
+	reactions := []models.PostReaction{}
+	if err := query.Preload("User").
+		Order("created_at DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&reactions).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve reactions", "error": err.Error(),
+		})
+	}
+
+	// 3. Return the list with the aggregated counts
+	decoratePost(c, post)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reactions retrieved successfully",
+		"data":    reactions,
+		"meta": fiber.Map{
+			"total":       total,
+			"limit":       page.Limit,
+			"offset":      page.Offset,
+			"counts":      post.Reactions,
+			"my_reaction": post.MyReaction,
+			"types":       reactionTypes(),
+		},
+	})
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRelatedLimit = 5
+	maxRelatedLimit     = 20
+	relatedCacheTTL     = 10 * time.Minute
+)
+
+// Weights of the related posts score. Title similarity is a trigram
+// similarity between 0 and 1.
+const (
+	relatedTagWeight      = 3.0 // Per shared tag
+	relatedCategoryWeight = 2.0
+	relatedTitleWeight    = 4.0
+)
+
+// trigramEnabled is set once the pg_trgm extension is available.
+// Without it related posts are scored by tags and category only.
+var trigramEnabled bool
+
+// relatedCache remembers the related post IDs of each post.
+// Only IDs are cached: posts are loaded fresh, so unpublished posts drop out.
+type relatedCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]relatedEntry
+}
+
+type relatedEntry struct {
+	ids     []uuid.UUID
+	expires time.Time
+}
+
+var relatedPosts = &relatedCache{entries: map[uuid.UUID]relatedEntry{}}
+
+func (rc *relatedCache) get(postID uuid.UUID) ([]uuid.UUID, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[postID]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.ids, true
+}
+
+func (rc *relatedCache) set(postID uuid.UUID, ids []uuid.UUID) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[postID] = relatedEntry{ids: ids, expires: time.Now().Add(relatedCacheTTL)}
+}
+
+// invalidateRelatedPosts drops every cached result. A changed post can
+// appear in, or drop out of, the related posts of any other post.
+func invalidateRelatedPosts() {
+	relatedPosts.mu.Lock()
+	defer relatedPosts.mu.Unlock()
+	relatedPosts.entries = map[uuid.UUID]relatedEntry{}
+}
+
+// EnableTextSimilarity installs pg_trgm and a trigram index on post titles
+func EnableTextSimilarity(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		log.Printf("Warning: pg_trgm is not available, related posts ignore titles: %v", err)
+		return
+	}
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_posts_title_trgm ON posts USING gin (title gin_trgm_ops)").Error; err != nil {
+		log.Printf("Warning: Failed to create trigram index: %v", err)
+	}
+	trigramEnabled = true
+}
+
+// findRelatedPostIDs scores the published posts against the post and
+// returns the best matches, best first
+func findRelatedPostIDs(post *models.Post) ([]uuid.UUID, error) {
+	var tagIDs []uuid.UUID
+	if err := database.DB.Table("post_tags").Where("post_id = ?", post.ID).Pluck("tag_id", &tagIDs).Error; err != nil {
+		return nil, err
+	}
+
+	// Scored parts, and the matching conditions that keep the candidate set small
+	score := "0"
+	match := "FALSE"
+	var scoreArgs, matchArgs []interface{}
+	if len(tagIDs) > 0 {
+		score += " + ? * (SELECT COUNT(*) FROM post_tags pt WHERE pt.post_id = posts.id AND pt.tag_id IN ?)"
+		scoreArgs = append(scoreArgs, relatedTagWeight, tagIDs)
+		match += " OR EXISTS (SELECT 1 FROM post_tags pt WHERE pt.post_id = posts.id AND pt.tag_id IN ?)"
+		matchArgs = append(matchArgs, tagIDs)
+	}
+	if post.CategoryID != nil {
+		score += " + CASE WHEN posts.category_id = ? THEN ? ELSE 0 END"
+		scoreArgs = append(scoreArgs, *post.CategoryID, relatedCategoryWeight)
+		match += " OR posts.category_id = ?"
+		matchArgs = append(matchArgs, *post.CategoryID)
+	}
+	if trigramEnabled {
+		score += " + ? * similarity(posts.title, ?)"
+		scoreArgs = append(scoreArgs, relatedTitleWeight, post.Title)
+		match += " OR posts.title % ?"
+		matchArgs = append(matchArgs, post.Title)
+	}
+
+	var rows []struct {
+		ID    uuid.UUID
+		Score float64
+	}
+	err := database.DB.Model(&models.Post{}).
+		Scopes(listedPosts).
+		Select("posts.id, ("+score+") AS score", scoreArgs...).
+		Where("posts.id <> ?", post.ID).
+		Where(match, matchArgs...).
+		Order("score DESC").
+		Order(postSortKey + " DESC").
+		Limit(maxRelatedLimit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		if row.Score > 0 {
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids, nil
+}
+
+// GetRelatedPosts is the handler for GET /api/posts/:id/related
+// Returns published posts similar to the post, scored by shared tags, same
+// category and title similarity. ?limit= caps the result (default 5, max 20).
+func GetRelatedPosts(c *fiber.Ctx) error {
+	// 1. Find the post and parse the limit
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canReadPost(c, post) {
+		return postNotFound(c)
+	}
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultRelatedLimit)))
+	if err != nil || limit < 1 || limit > maxRelatedLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "limit must be between 1 and 20",
+		})
+	}
+
+	// 2. Score the candidates, or reuse the cached result
+	ids, ok := relatedPosts.get(post.ID)
+	if !ok {
+		if ids, err = findRelatedPostIDs(post); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to find related posts", "error": err.Error(),
+			})
+		}
+		relatedPosts.set(post.ID, ids)
+	}
+
+	// 3. Load the posts and put them back in score order
+	posts := []models.Post{}
+	if len(ids) > 0 {
+		if err := database.DB.
+			Preload("Author").
+			Preload("Tags").
+			Scopes(listedPosts).
+			Omit("content", "content_html", "toc").
+			Where("posts.id IN ?", ids).
+			Find(&posts).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to retrieve related posts", "error": err.Error(),
+			})
+		}
+	}
+	rank := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	sort.Slice(posts, func(i, j int) bool { return rank[posts[i].ID] < rank[posts[j].ID] })
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+
+	decoratePosts(c, posts)
+
+	// 4. Return the response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Related posts retrieved successfully",
+		"data":    posts,
+	})
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// metaDescriptionLength is about what search engines show of a description
+const metaDescriptionLength = 160
+
+// postSEO is a post's metadata after falling back to the defaults
+type postSEO struct {
+	Title        string
+	Description  string
+	CanonicalURL string
+	Image        string
+}
+
+// truncateWords shortens text to at most max characters, cutting at a word boundary
+func truncateWords(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)[:max-1]
+	cut := string(runes)
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// resolvePostSEO fills the post's empty SEO fields from its title,
+// excerpt, featured image and frontend URL
+func resolvePostSEO(post *models.Post) postSEO {
+	seo := postSEO{
+		Title:        post.MetaTitle,
+		Description:  post.MetaDescription,
+		CanonicalURL: post.CanonicalURL,
+		Image:        post.SocialImageURL,
+	}
+	if seo.Title == "" {
+		seo.Title = post.Title
+	}
+	if seo.Description == "" {
+		seo.Description = truncateWords(post.Excerpt, metaDescriptionLength)
+	}
+	if seo.CanonicalURL == "" {
+		seo.CanonicalURL = postURL(post)
+	}
+	if seo.Image == "" {
+		seo.Image = post.FeaturedImageURL
+	}
+	return seo
+}
+
+// GetPostMeta is the handler for GET /api/posts/:id/meta
+// Returns ready-to-render Open Graph and Twitter card tags and JSON-LD
+// Article structured data for the post's page.
+func GetPostMeta(c *fiber.Ctx) error {
+	// 1. Find the post
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canReadPost(c, post) {
+		return postNotFound(c)
+	}
+	database.DB.Preload("Author").Preload("Tags").First(post, post.ID)
+
+	seo := resolvePostSEO(post)
+	published := feedDate(post).UTC().Format(time.RFC3339)
+	modified := post.UpdatedAt.UTC().Format(time.RFC3339)
+	tags := postTagNames(post)
+
+	// 2. Open Graph
+	openGraph := fiber.Map{
+		"og:type":                "article",
+		"og:title":               seo.Title,
+		"og:description":         seo.Description,
+		"og:url":                 seo.CanonicalURL,
+		"og:site_name":           config.AppConfig.SiteTitle,
+		"article:published_time": published,
+		"article:modified_time":  modified,
+		"article:author":         authorURL(post.AuthorID),
+		"article:section":        post.Category,
+		"article:tag":            tags,
+	}
+
+	// 3. Twitter card, large when there is an image to show
+	twitter := fiber.Map{
+		"twitter:card":        "summary",
+		"twitter:title":       seo.Title,
+		"twitter:description": seo.Description,
+	}
+	if seo.Image != "" {
+		openGraph["og:image"] = seo.Image
+		twitter["twitter:card"] = "summary_large_image"
+		twitter["twitter:image"] = seo.Image
+	}
+
+	// 4. JSON-LD Article
+	article := fiber.Map{
+		"@context":      "https://schema.org",
+		"@type":         "Article",
+		"headline":      seo.Title,
+		"description":   seo.Description,
+		"datePublished": published,
+		"dateModified":  modified,
+		"author": fiber.Map{
+			"@type": "Person",
+			"name":  post.Author.FullName,
+			"url":   authorURL(post.AuthorID),
+		},
+		"publisher": fiber.Map{
+			"@type": "Organization",
+			"name":  config.AppConfig.SiteTitle,
+			"url":   config.AppConfig.SiteURL,
+		},
+		"mainEntityOfPage": fiber.Map{
+			"@type": "WebPage",
+			"@id":   seo.CanonicalURL,
+		},
+		"articleSection": post.Category,
+		"keywords":       strings.Join(tags, ", "),
+		"wordCount":      post.WordCount,
+	}
+	if seo.Image != "" {
+		article["image"] = []string{seo.Image}
+	}
+
+	// 5. hreflang alternates: every published language version, and
+	// x-default pointing at the default-language version
+	alternates := []fiber.Map{}
+	posts := []models.Post{*post}
+	attachTranslations(posts)
+	xDefault := ""
+	for _, translation := range posts[0].Translations {
+		alternates = append(alternates, fiber.Map{"hreflang": translation.Locale, "href": translation.URL})
+		if translation.Locale == config.AppConfig.DefaultLocale {
+			xDefault = translation.URL
+		}
+	}
+	if len(alternates) > 0 && xDefault != "" {
+		alternates = append(alternates, fiber.Map{"hreflang": "x-default", "href": xDefault})
+	}
+	openGraph["og:locale"] = post.Locale
+	article["inLanguage"] = post.Locale
+
+	// 6. Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post metadata retrieved successfully",
+		"data": fiber.Map{
+			"title":         seo.Title,
+			"description":   seo.Description,
+			"canonical_url": seo.CanonicalURL,
+			"image":         seo.Image,
+			"locale":        post.Locale,
+			"alternates":    alternates,
+			"open_graph":    openGraph,
+			"twitter":       twitter,
+			"json_ld":       article,
+		},
+	})
+}
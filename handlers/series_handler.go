@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+	"github.com/mohamadsolkhannawawi/article-backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SeriesRequest is the struct for creating or updating a series
+type SeriesRequest struct {
+	Title       string `json:"title" validate:"required,min=3,max=200"`
+	Slug        string `json:"slug" validate:"omitempty,max=220"` // Derived from the title if empty
+	Description string `json:"description" validate:"omitempty,max=2000"`
+}
+
+// SeriesPostsRequest is the struct for setting the parts of a series.
+// The order of the list is the reading order; a series has at most 100 parts.
+type SeriesPostsRequest struct {
+	PostIDs []uuid.UUID `json:"post_ids" validate:"max=100"`
+}
+
+// seriesSlug turns a title or requested slug into a URL slug
+func seriesSlug(value string) string {
+	if slug := utils.Slugify(value); slug != "" {
+		return slug
+	}
+	return "series-" + uuid.NewString()[:8]
+}
+
+// seriesPublishedPosts loads the published parts of a series in order.
+// Unlisted posts are left out like in every other listing.
+func seriesPublishedPosts(seriesID uuid.UUID) ([]models.Post, error) {
+	posts := []models.Post{}
+	err := database.DB.Model(&models.Post{}).
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id").
+		Where("series_posts.series_id = ?", seriesID).
+		Scopes(listedPosts).
+		Preload("Author").
+		Preload("Tags").
+		Omit("content", "content_html", "toc").
+		Order("series_posts.position ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// attachSeriesCounts sets PostCount to the number of published, listed parts
+func attachSeriesCounts(series []models.Series) {
+	if len(series) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, len(series))
+	for i := range series {
+		ids[i] = series[i].ID
+	}
+
+	var rows []struct {
+		SeriesID uuid.UUID
+		Count    int64
+	}
+	database.DB.Model(&models.Post{}).
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id").
+		Scopes(listedPosts).
+		Select("series_posts.series_id, COUNT(*) AS count").
+		Where("series_posts.series_id IN ?", ids).
+		Group("series_posts.series_id").
+		Scan(&rows)
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SeriesID] = row.Count
+	}
+	for i := range series {
+		series[i].PostCount = counts[series[i].ID]
+	}
+}
+
+// seriesNavigation places the post within its series, or returns nil when
+// it isn't part of one. Unpublished and unlisted parts are skipped, except
+// the post itself so its author (or a reader with the link) sees where it is.
+func seriesNavigation(post *models.Post) *models.SeriesNavigation {
+	var item models.SeriesPost
+	if err := database.DB.Where("post_id = ?", post.ID).First(&item).Error; err != nil {
+		return nil
+	}
+	var series models.Series
+	if err := database.DB.First(&series, item.SeriesID).Error; err != nil {
+		return nil
+	}
+
+	var parts []models.SeriesPostLink
+	database.DB.Model(&models.Post{}).
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id").
+		Where("series_posts.series_id = ?", series.ID).
+		Where("posts.id = ? OR posts.id IN (?)", post.ID, database.DB.Model(&models.Post{}).Scopes(listedPosts).Select("posts.id")).
+		Order("series_posts.position ASC").
+		Select("posts.id, posts.title").
+		Scan(&parts)
+
+	nav := &models.SeriesNavigation{ID: series.ID, Title: series.Title, Slug: series.Slug}
+	for i, part := range parts {
+		if part.ID != post.ID {
+			continue
+		}
+		nav.Position = i + 1
+		if i > 0 {
+			nav.Previous = &parts[i-1]
+		}
+		if i+1 < len(parts) {
+			nav.Next = &parts[i+1]
+		}
+	}
+	nav.Total = len(parts)
+	if post.Status != models.PostStatusPublish || post.Visibility == models.PostVisibilityUnlisted {
+		nav.Total-- // The post itself isn't a listed part
+	}
+	return nav
+}
+
+// findSeries loads the series in :id, by ID or by slug
+func findSeries(c *fiber.Ctx) (*models.Series, error) {
+	var series models.Series
+	query := database.DB.Preload("Author")
+	if id, err := uuid.Parse(c.Params("id")); err == nil {
+		query = query.Where("id = ?", id)
+	} else {
+		query = query.Where("slug = ?", c.Params("slug", c.Params("id")))
+	}
+	if err := query.First(&series).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Series not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	return &series, nil
+}
+
+// findOwnSeries is findSeries for changes: only the series' author or an admin may make them
+func findOwnSeries(c *fiber.Ctx) (*models.Series, error) {
+	series, err := findSeries(c)
+	if series == nil {
+		return nil, err
+	}
+	userID, _ := currentUserID(c)
+	if series.AuthorID != userID && !isAdmin(c) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to change this series",
+		})
+	}
+	return series, nil
+}
+
+// parseSeriesRequest parses and validates a SeriesRequest body
+func parseSeriesRequest(c *fiber.Ctx) (*SeriesRequest, error) {
+	req := new(SeriesRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	return req, nil
+}
+
+// applySeriesRequest copies the request onto the series
+func applySeriesRequest(series *models.Series, req *SeriesRequest) {
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Title
+	}
+	series.Title = strings.Join(strings.Fields(req.Title), " ")
+	series.Slug = seriesSlug(slug)
+	series.Description = req.Description
+}
+
+// GetSeriesList is the handler for GET /api/series
+// Returns every series with its number of published parts, newest first.
+// ?author_id= limits the list to one author's series.
+func GetSeriesList(c *fiber.Ctx) error {
+	query := database.DB.Preload("Author").Order("created_at DESC")
+	if authorID := c.Query("author_id"); authorID != "" {
+		id, err := uuid.Parse(authorID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Invalid author ID format", "error": err.Error(),
+			})
+		}
+		query = query.Where("author_id = ?", id)
+	}
+
+	series := []models.Series{}
+	if err := query.Find(&series).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve series", "error": err.Error(),
+		})
+	}
+	attachSeriesCounts(series)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Series retrieved successfully",
+		"data":    series,
+	})
+}
+
+// GetSeries is the handler for GET /api/series/:slug
+// Returns the series with its published parts in reading order.
+func GetSeries(c *fiber.Ctx) error {
+	// 1. Find the series
+	series, err := findSeries(c)
+	if series == nil {
+		return err
+	}
+
+	// 2. Load its published parts
+	posts, err := seriesPublishedPosts(series.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve series posts", "error": err.Error(),
+		})
+	}
+	decoratePosts(c, posts)
+	series.PostCount = int64(len(posts))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Series retrieved successfully",
+		"data":    fiber.Map{"series": series, "posts": posts},
+	})
+}
+
+// CreateSeries is the handler for POST /api/series (PROTECTED)
+func CreateSeries(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid user data in token",
+		})
+	}
+	req, err := parseSeriesRequest(c)
+	if req == nil {
+		return err
+	}
+
+	series := models.Series{ID: uuid.New(), AuthorID: userID}
+	applySeriesRequest(&series, req)
+	if err := database.DB.Omit("Author").Create(&series).Error; err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error", "message": "A series with this slug already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to create series", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Series created successfully",
+		"data":    series,
+	})
+}
+
+// UpdateSeries is the handler for PUT /api/series/:id (series author or admin)
+func UpdateSeries(c *fiber.Ctx) error {
+	series, err := findOwnSeries(c)
+	if series == nil {
+		return err
+	}
+	req, err := parseSeriesRequest(c)
+	if req == nil {
+		return err
+	}
+
+	applySeriesRequest(series, req)
+	if err := database.DB.Omit("Author").Save(series).Error; err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error", "message": "A series with this slug already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update series", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Series updated successfully",
+		"data":    series,
+	})
+}
+
+// DeleteSeries is the handler for DELETE /api/series/:id (series author or admin)
+// The posts themselves are kept; they just no longer belong to a series.
+func DeleteSeries(c *fiber.Ctx) error {
+	series, err := findOwnSeries(c)
+	if series == nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(series).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to delete series", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Series deleted successfully",
+	})
+}
+
+// SetSeriesPosts is the handler for PUT /api/series/:id/posts (series author or admin)
+// Replaces the parts of the series with post_ids, in reading order. The
+// caller must be able to edit every post, and a post can only be in one series.
+func SetSeriesPosts(c *fiber.Ctx) error {
+	// 1. Find the series
+	series, err := findOwnSeries(c)
+	if series == nil {
+		return err
+	}
+
+	// 2. Parse and validate the request body
+	req := new(SeriesPostsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	// 3. Check the posts exist and the caller may add them
+	items := make([]models.SeriesPost, 0, len(req.PostIDs))
+	seen := map[uuid.UUID]bool{}
+	for i, postID := range req.PostIDs {
+		if seen[postID] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Each post can only be listed once",
+			})
+		}
+		seen[postID] = true
+
+		var post models.Post
+		if err := database.DB.First(&post, postID).Error; err != nil || post.Status == models.PostStatusTrash {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error", "message": "Post not found: " + postID.String(),
+			})
+		}
+		if !canEditPost(c, &post) && !isAdmin(c) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status": "error", "message": "You are not authorized to add this post: " + postID.String(),
+			})
+		}
+		items = append(items, models.SeriesPost{SeriesID: series.ID, PostID: postID, Position: i + 1})
+	}
+
+	// 4. Replace the parts
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status": "error", "message": "One of the posts already belongs to another series",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to update series posts", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Series posts updated successfully",
+		"data":    items,
+	})
+}
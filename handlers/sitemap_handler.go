@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// maxSitemapURLs is the most URLs a single sitemap may list.
+// Larger sites get a sitemap index pointing at numbered sitemaps.
+const maxSitemapURLs = 50000
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	NS      string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name         `xml:"sitemapindex"`
+	NS       string           `xml:"xmlns,attr"`
+	Sitemaps []sitemapPointer `xml:"sitemap"`
+}
+
+type sitemapPointer struct {
+	Loc string `xml:"loc"`
+}
+
+func sitemapDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// sitemapSection is one kind of URL in the sitemap. All sections are laid
+// out one after the other and cut into pages of maxSitemapURLs.
+type sitemapSection struct {
+	count func() (int64, error)
+	load  func(offset, limit int) ([]sitemapURL, error)
+}
+
+// sitemapSections lists, in order: the home page, categories, tags with
+// published posts, and published posts
+func sitemapSections() []sitemapSection {
+	taggedPosts := func() *gorm.DB {
+		published := database.DB.Model(&models.Post{}).Scopes(listedPosts).Select("posts.id, posts.updated_at")
+		return database.DB.Table("tags").
+			Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+			Joins("JOIN (?) AS published ON published.id = post_tags.post_id", published)
+	}
+
+	return []sitemapSection{
+		{
+			count: func() (int64, error) { return 1, nil },
+			load: func(offset, limit int) ([]sitemapURL, error) {
+				return []sitemapURL{{Loc: config.AppConfig.SiteURL + "/"}}, nil
+			},
+		},
+		{
+			count: func() (int64, error) {
+				var n int64
+				err := database.DB.Model(&models.Category{}).Count(&n).Error
+				return n, err
+			},
+			load: func(offset, limit int) ([]sitemapURL, error) {
+				var categories []models.Category
+				err := database.DB.Order("slug ASC").Offset(offset).Limit(limit).Find(&categories).Error
+				urls := make([]sitemapURL, 0, len(categories))
+				for _, category := range categories {
+					urls = append(urls, sitemapURL{Loc: categoryURL(category.Slug), LastMod: sitemapDate(category.UpdatedAt)})
+				}
+				return urls, err
+			},
+		},
+		{
+			count: func() (int64, error) {
+				var n int64
+				err := taggedPosts().Distinct("tags.id").Count(&n).Error
+				return n, err
+			},
+			load: func(offset, limit int) ([]sitemapURL, error) {
+				var rows []struct {
+					Name    string
+					LastMod time.Time
+				}
+				err := taggedPosts().
+					Select("tags.name, MAX(published.updated_at) AS last_mod").
+					Group("tags.name").
+					Order("tags.name ASC").
+					Offset(offset).Limit(limit).
+					Scan(&rows).Error
+				urls := make([]sitemapURL, 0, len(rows))
+				for _, row := range rows {
+					urls = append(urls, sitemapURL{Loc: tagURL(row.Name), LastMod: sitemapDate(row.LastMod)})
+				}
+				return urls, err
+			},
+		},
+		{
+			count: func() (int64, error) {
+				var n int64
+				err := database.DB.Model(&models.Post{}).Scopes(listedPosts).Count(&n).Error
+				return n, err
+			},
+			load: func(offset, limit int) ([]sitemapURL, error) {
+				var posts []models.Post
+				err := database.DB.Model(&models.Post{}).
+					Scopes(listedPosts).
+					Select("id", "updated_at").
+					Order("created_at ASC, id ASC"). // New posts are appended, so pages stay stable
+					Offset(offset).Limit(limit).
+					Find(&posts).Error
+				urls := make([]sitemapURL, 0, len(posts))
+				for i := range posts {
+					urls = append(urls, sitemapURL{Loc: postURL(&posts[i]), LastMod: sitemapDate(posts[i].UpdatedAt)})
+				}
+				return urls, err
+			},
+		},
+	}
+}
+
+// countSitemap counts the URLs of each section and of the whole sitemap
+func countSitemap(sections []sitemapSection) ([]int64, int64, error) {
+	counts := make([]int64, len(sections))
+	var total int64
+	for i, section := range sections {
+		n, err := section.count()
+		if err != nil {
+			return nil, 0, err
+		}
+		counts[i] = n
+		total += n
+	}
+	return counts, total, nil
+}
+
+// sitemapPage loads the URLs of one page (0-based) of the sitemap, given
+// the counts of its sections
+func sitemapPage(sections []sitemapSection, counts []int64, page int) ([]sitemapURL, error) {
+	// Walk the sections, taking the part of each that falls on the page
+	start := int64(page) * maxSitemapURLs
+	end := start + maxSitemapURLs
+	urls := []sitemapURL{}
+	var offset int64
+	for i, section := range sections {
+		from, to := max(start, offset), min(end, offset+counts[i])
+		if from < to {
+			part, err := section.load(int(from-offset), int(to-from))
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, part...)
+		}
+		offset += counts[i]
+	}
+	return urls, nil
+}
+
+// GetSitemap is the handler for GET /sitemap.xml
+// Lists every public page of the site, or a sitemap index when there are
+// more than 50,000 of them. Only the counts are needed for an index.
+func GetSitemap(c *fiber.Ctx) error {
+	sections := sitemapSections()
+	counts, total, err := countSitemap(sections)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to build sitemap", "error": err.Error(),
+		})
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	if total <= maxSitemapURLs {
+		urls, err := sitemapPage(sections, counts, 0)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error", "message": "Failed to build sitemap", "error": err.Error(),
+			})
+		}
+		return sendXML(c, "application/xml; charset=utf-8", sitemapURLSet{NS: sitemapNS, URLs: urls})
+	}
+
+	pages := int((total + maxSitemapURLs - 1) / maxSitemapURLs)
+	index := sitemapIndex{NS: sitemapNS, Sitemaps: make([]sitemapPointer, 0, pages)}
+	for page := 1; page <= pages; page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapPointer{
+			Loc: c.BaseURL() + "/sitemaps/" + strconv.Itoa(page) + ".xml",
+		})
+	}
+	return sendXML(c, "application/xml; charset=utf-8", index)
+}
+
+// GetSitemapPage is the handler for GET /sitemaps/:page.xml (pages start at 1)
+func GetSitemapPage(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Params("page"))
+	if err != nil || page < 1 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Sitemap not found",
+		})
+	}
+	sections := sitemapSections()
+	counts, _, err := countSitemap(sections)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to build sitemap", "error": err.Error(),
+		})
+	}
+	urls, err := sitemapPage(sections, counts, page-1)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to build sitemap", "error": err.Error(),
+		})
+	}
+	if len(urls) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Sitemap not found",
+		})
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return sendXML(c, "application/xml; charset=utf-8", sitemapURLSet{NS: sitemapNS, URLs: urls})
+}
+
+// GetRobots is the handler for GET /robots.txt
+// Crawlers may read the feeds and sitemap but not the JSON API.
+func GetRobots(c *fiber.Ctx) error {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	b.WriteString("Disallow: /api/\n")
+	b.WriteString("Allow: /\n\n")
+	b.WriteString("Sitemap: " + c.BaseURL() + "/sitemap.xml\n")
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return c.SendString(b.String())
+}
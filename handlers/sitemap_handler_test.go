@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSitemapPage(t *testing.T) {
+	type load struct{ section, offset, limit int }
+
+	tests := []struct {
+		name   string
+		counts []int64
+		page   int
+		want   []load
+	}{
+		{
+			name:   "everything on the first page",
+			counts: []int64{1, 3, 2, 10},
+			want:   []load{{0, 0, 1}, {1, 0, 3}, {2, 0, 2}, {3, 0, 10}},
+		},
+		{
+			name:   "empty sections are skipped",
+			counts: []int64{1, 0, 0, 5},
+			want:   []load{{0, 0, 1}, {3, 0, 5}},
+		},
+		{
+			name:   "exactly one full page",
+			counts: []int64{1, 9, 0, maxSitemapURLs - 10},
+			want:   []load{{0, 0, 1}, {1, 0, 9}, {3, 0, maxSitemapURLs - 10}},
+		},
+		{
+			name:   "nothing after the last page",
+			counts: []int64{1, 9, 0, maxSitemapURLs - 10},
+			page:   1,
+			want:   nil,
+		},
+		{
+			name:   "a section split across pages, first part",
+			counts: []int64{1, 20, 30, maxSitemapURLs},
+			want:   []load{{0, 0, 1}, {1, 0, 20}, {2, 0, 30}, {3, 0, maxSitemapURLs - 51}},
+		},
+		{
+			name:   "a section split across pages, rest",
+			counts: []int64{1, 20, 30, maxSitemapURLs},
+			page:   1,
+			want:   []load{{3, maxSitemapURLs - 51, 51}},
+		},
+		{
+			name:   "a middle page of one long section",
+			counts: []int64{1, 0, 0, 3*maxSitemapURLs + 7},
+			page:   2,
+			want:   []load{{3, 2*maxSitemapURLs - 1, maxSitemapURLs}},
+		},
+		{
+			name:   "the last, partial page",
+			counts: []int64{1, 0, 0, 3*maxSitemapURLs + 7},
+			page:   3,
+			want:   []load{{3, 3*maxSitemapURLs - 1, 8}},
+		},
+		{
+			name:   "a page boundary between sections",
+			counts: []int64{1, maxSitemapURLs - 1, 4},
+			page:   1,
+			want:   []load{{2, 0, 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loads []load
+			sections := make([]sitemapSection, len(tt.counts))
+			for i := range sections {
+				sections[i].load = func(offset, limit int) ([]sitemapURL, error) {
+					loads = append(loads, load{i, offset, limit})
+					return make([]sitemapURL, limit), nil
+				}
+			}
+
+			urls, err := sitemapPage(sections, tt.counts, tt.page)
+			if err != nil {
+				t.Fatalf("sitemapPage returned %v", err)
+			}
+			if !reflect.DeepEqual(loads, tt.want) {
+				t.Errorf("sitemapPage(%v, page %d) loaded %v, want %v", tt.counts, tt.page, loads, tt.want)
+			}
+			wantURLs := 0
+			for _, l := range tt.want {
+				wantURLs += l.limit
+			}
+			if len(urls) != wantURLs || len(urls) > maxSitemapURLs {
+				t.Errorf("sitemapPage(%v, page %d) returned %d URLs, want %d", tt.counts, tt.page, len(urls), wantURLs)
+			}
+		})
+	}
+}
+
+func TestCountSitemap(t *testing.T) {
+	sections := []sitemapSection{
+		{count: func() (int64, error) { return 1, nil }},
+		{count: func() (int64, error) { return 0, nil }},
+		{count: func() (int64, error) { return maxSitemapURLs, nil }},
+	}
+	counts, total, err := countSitemap(sections)
+	if err != nil {
+		t.Fatalf("countSitemap returned %v", err)
+	}
+	if !reflect.DeepEqual(counts, []int64{1, 0, maxSitemapURLs}) || total != maxSitemapURLs+1 {
+		t.Errorf("countSitemap = %v, %d", counts, total)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxStatsDays = 365
+
+// GetPostStats is the handler for GET /api/posts/:id/stats (PROTECTED)
+// Returns daily views and top referrers for the last ?days= days (default 30).
+func GetPostStats(c *fiber.Ctx) error {
+	// 1. Find the post and check the caller owns it
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canManagePost(c, post) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to view these stats",
+		})
+	}
+
+	// 2. Parse the date range
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil || days < 1 || days > maxStatsDays {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "days must be between 1 and 365",
+		})
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	// 3. Daily views, with missing days filled in as zero
+	var stats []models.PostViewStat
+	if err := database.DB.
+		Where("post_id = ? AND date >= ?", post.ID, from).
+		Order("date ASC").
+		Find(&stats).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve stats", "error": err.Error(),
+		})
+	}
+	byDay := make(map[string]int64, len(stats))
+	var rangeTotal int64
+	for _, stat := range stats {
+		byDay[stat.Date.Format("2006-01-02")] = stat.Views
+		rangeTotal += stat.Views
+	}
+	daily := make([]fiber.Map, 0, days)
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		daily = append(daily, fiber.Map{"date": key, "views": byDay[key]})
+	}
+
+	// 4. Top referrers over the same range
+	var referrers []struct {
+		Referrer string `json:"referrer"`
+		Views    int64  `json:"views"`
+	}
+	if err := database.DB.Model(&models.PostReferrerStat{}).
+		Select("referrer, SUM(views) AS views").
+		Where("post_id = ? AND date >= ?", post.ID, from).
+		Group("referrer").
+		Order("views DESC").
+		Limit(20).
+		Scan(&referrers).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve referrers", "error": err.Error(),
+		})
+	}
+
+	// 5. Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post stats retrieved successfully",
+		"data": fiber.Map{
+			"post_id":     post.ID,
+			"total_views": post.ViewCount,
+			"range_views": rangeTotal,
+			"days":        days,
+			"daily":       daily,
+			"referrers":   referrers,
+		},
+	})
+}
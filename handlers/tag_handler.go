@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultTagLimit = 20
+	maxTagLimit     = 100
+)
+
+// RenameTagRequest is the struct for renaming a tag
+type RenameTagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// MergeTagRequest is the struct for merging a tag into another one
+type MergeTagRequest struct {
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
+
+// tagSummary is a tag together with its number of published posts
+type tagSummary struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	PostCount int64     `json:"post_count"`
+}
+
+// normalizeTagName lowercases a tag name and collapses its whitespace,
+// so "Go", "go " and "GO" are the same tag
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// normalizeTagNames normalizes the names, dropping blanks and duplicates
+func normalizeTagNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = normalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// resolveTags finds or creates the tags with the given names
+func resolveTags(tx *gorm.DB, names []string) ([]*models.Tag, error) {
+	tags := []*models.Tag{}
+	for _, tagName := range normalizeTagNames(names) {
+		var tag models.Tag
+		// Auto-create tag if it doesn't exist
+		if err := tx.FirstOrCreate(&tag, models.Tag{Name: tagName}).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, nil
+}
+
+// mergeTag moves every post of the source tag to the target tag and
+// deletes the source. Posts that already had both keep a single row.
+func mergeTag(tx *gorm.DB, sourceID, targetID uuid.UUID) error {
+	if err := tx.Exec(
+		`INSERT INTO post_tags (post_id, tag_id)
+		SELECT post_id, ? FROM post_tags WHERE tag_id = ?
+		ON CONFLICT DO NOTHING`, targetID, sourceID).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM post_tags WHERE tag_id = ?", sourceID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.Tag{}, sourceID).Error
+}
+
+// NormalizeTags normalizes the names of existing tags, merging tags that
+// only differed in case or whitespace into one
+func NormalizeTags(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+
+	var tags []models.Tag
+	if err := db.Order("name ASC").Find(&tags).Error; err != nil {
+		log.Printf("ERROR: Failed to load tags: %v", err)
+		return
+	}
+
+	// Group the tags by normalized name; a tag already using it wins
+	groups := map[string][]models.Tag{}
+	for _, tag := range tags {
+		name := normalizeTagName(tag.Name)
+		if tag.Name == name {
+			groups[name] = append([]models.Tag{tag}, groups[name]...)
+		} else {
+			groups[name] = append(groups[name], tag)
+		}
+	}
+
+	changed := 0
+	for name, group := range groups {
+		target := group[0]
+		if target.Name == name && len(group) == 1 {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, source := range group[1:] {
+				if err := mergeTag(tx, source.ID, target.ID); err != nil {
+					return err
+				}
+			}
+			return tx.Model(&target).Update("name", name).Error
+		})
+		if err != nil {
+			log.Printf("ERROR: Failed to normalize tag %q: %v", name, err)
+			continue
+		}
+		changed++
+	}
+	if changed > 0 {
+		log.Printf("✓ Normalized %d tags", changed)
+	}
+}
+
+// findTagByID loads the tag in the :id route parameter.
+// When it returns a nil tag the error response has already been sent.
+func findTagByID(c *fiber.Ctx) (*models.Tag, error) {
+	tagID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid tag ID format", "error": err.Error(),
+		})
+	}
+	var tag models.Tag
+	if err := database.DB.First(&tag, tagID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Tag not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+	return &tag, nil
+}
+
+// GetTags is the handler for GET /api/tags
+// Lists tags with their number of published posts, most used first. Tags
+// without listed posts are left out, so drafts and unlisted posts don't
+// reveal their tags.
+// ?q= filters by name prefix for autocomplete, ?limit= caps the result.
+func GetTags(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultTagLimit)))
+	if err != nil || limit < 1 || limit > maxTagLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "limit must be between 1 and 100",
+		})
+	}
+
+	published := database.DB.Model(&models.Post{}).Scopes(listedPosts).Select("posts.id")
+	query := database.DB.Table("tags").
+		Select("tags.id, tags.name, COUNT(published.id) AS post_count").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("JOIN (?) AS published ON published.id = post_tags.post_id", published).
+		Group("tags.id, tags.name").
+		Order("post_count DESC, tags.name ASC").
+		Limit(limit)
+	if prefix := normalizeTagName(c.Query("q")); prefix != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+		query = query.Where("tags.name LIKE ?", escaped+"%")
+	}
+
+	tags := []tagSummary{}
+	if err := query.Scan(&tags).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve tags", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tags retrieved successfully",
+		"data":    tags,
+	})
+}
+
+// GetTagPosts is the handler for GET /api/tags/:name/posts
+// Lists published posts with the tag, paginated like GET /api/posts.
+func GetTagPosts(c *fiber.Ctx) error {
+	// 1. Find the tag by its (normalized) name
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid tag name", "error": err.Error(),
+		})
+	}
+	var tag models.Tag
+	if err := database.DB.Where("name = ?", normalizeTagName(name)).First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status": "error", "message": "Tag not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Database error", "error": err.Error(),
+		})
+	}
+
+	// 2. Parse pagination parameters
+	page, err := parsePageParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid pagination parameters", "error": err.Error(),
+		})
+	}
+
+	// 3. Count and fetch the posts
+	var posts []models.Post
+	var total int64
+	query := database.DB.Model(&models.Post{}).
+		Preload("Author").
+		Preload("Tags").
+		Scopes(listedPosts).
+		Where("posts.id IN (?)", database.DB.Table("post_tags").Select("post_id").Where("tag_id = ?", tag.ID))
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to count posts", "error": err.Error(),
+		})
+	}
+	if !includeContent(c) {
+		query = query.Omit("content", "content_html", "toc")
+	}
+	hasMore, err := paginatePosts(query, page, &posts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve posts", "error": err.Error(),
+		})
+	}
+
+	decoratePosts(c, posts)
+
+	// 4. Return the response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Posts retrieved successfully",
+		"data":    posts,
+		"meta":    pageMeta(posts, total, page, hasMore),
+	})
+}
+
+// RenameTag is the handler for PUT /api/tags/:id (admins only)
+// Renaming to the name of another tag is refused; merge the tags instead.
+func RenameTag(c *fiber.Ctx) error {
+	if !isAdmin(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Admin access required",
+		})
+	}
+	tag, err := findTagByID(c)
+	if tag == nil {
+		return err
+	}
+
+	req := new(RenameTagRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	name := normalizeTagName(req.Name)
+	req.Name = name
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+
+	var existing int64
+	database.DB.Model(&models.Tag{}).Where("name = ? AND id <> ?", name, tag.ID).Count(&existing)
+	if existing > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "Another tag already has this name, merge the tags instead",
+		})
+	}
+
+	if err := database.DB.Model(tag).Update("name", name).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to rename tag", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tag renamed successfully",
+		"data":    tag,
+	})
+}
+
+// MergeTag is the handler for POST /api/tags/:id/merge (admins only)
+// Moves every post of the tag in :id to the target tag and deletes it.
+func MergeTag(c *fiber.Ctx) error {
+	if !isAdmin(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Admin access required",
+		})
+	}
+	source, err := findTagByID(c)
+	if source == nil {
+		return err
+	}
+
+	req := new(MergeTagRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if req.TargetID == source.ID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "A tag cannot be merged into itself",
+		})
+	}
+
+	var target models.Tag
+	if err := database.DB.First(&target, req.TargetID).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status": "error", "message": "Target tag not found",
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock both tags so concurrent merges can't interleave
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uuid.UUID{source.ID, target.ID}).
+			Find(&[]models.Tag{}).Error; err != nil {
+			return err
+		}
+		return mergeTag(tx, source.ID, target.ID)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to merge tags", "error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tags merged successfully",
+		"data":    target,
+	})
+}
+
+// DeleteUnusedTags is the handler for DELETE /api/tags/unused (admins only)
+// Deletes every tag that is not attached to any post.
+func DeleteUnusedTags(c *fiber.Ctx) error {
+	if !isAdmin(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Admin access required",
+		})
+	}
+
+	result := database.DB.
+		Where("NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id)").
+		Delete(&models.Tag{})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to delete unused tags", "error": result.Error.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Unused tags deleted successfully",
+		"data":    fiber.Map{"deleted": result.RowsAffected},
+	})
+}
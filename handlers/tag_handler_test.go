@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTagName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Go", "go"},
+		{"go ", "go"},
+		{"  Web   Development\t", "web development"},
+		{"ÜBER", "über"},
+		{"", ""},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeTagName(tt.in); got != tt.want {
+			t.Errorf("normalizeTagName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTagNames(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"drops duplicates after normalizing", []string{"Go", "go ", "GO"}, []string{"go"}},
+		{"drops blanks", []string{"", "  ", "web"}, []string{"web"}},
+		{"keeps first-seen order", []string{"Web", "Go", "web"}, []string{"web", "go"}},
+		{"nil", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeTagNames(tt.in)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || got == nil {
+				t.Errorf("normalizeTagNames(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+	"github.com/mohamadsolkhannawawi/article-backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// cloudinaryURL finds Cloudinary image URLs embedded in post content
+var cloudinaryURL = regexp.MustCompile(`https://res\.cloudinary\.com/[^\s"'()<>]+`)
+
+// purgePost permanently deletes a post together with every row that
+// references it. It must run inside a transaction.
+func purgePost(tx *gorm.DB, post *models.Post) error {
+	// Join tables and per-post data first, the post row last
+	dependents := []interface{}{
+		&models.Comment{},
+		&models.PostReaction{},
+		&models.Bookmark{},
+		&models.ReadingListItem{},
+		&models.PostViewStat{},
+		&models.PostReferrerStat{},
+		&models.PostReview{},
+		&models.PostAuthor{},
+		&models.SeriesPost{},
+	}
+	if err := tx.Model(post).Association("Tags").Clear(); err != nil {
+		return err
+	}
+	for _, model := range dependents {
+		if err := tx.Unscoped().Where("post_id = ?", post.ID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Delete(post).Error
+}
+
+// postImageURLs lists the uploaded images a post uses
+func postImageURLs(post *models.Post) []string {
+	urls := cloudinaryURL.FindAllString(post.Content, -1)
+	if post.FeaturedImageURL != "" {
+		urls = append(urls, post.FeaturedImageURL)
+	}
+	if post.SocialImageURL != "" {
+		urls = append(urls, post.SocialImageURL)
+	}
+	return urls
+}
+
+// imageInUse reports whether any post, trashed ones included, still uses
+// the image. Lookup errors count as in use, so nothing is deleted by mistake.
+func imageInUse(url string) bool {
+	var count int64
+	err := database.DB.Unscoped().Model(&models.Post{}).
+		Where("featured_image_url = ? OR social_image_url = ? OR strpos(content, ?) > 0", url, url, url).
+		Count(&count).Error
+	if err != nil {
+		log.Printf("Error checking whether image %s is in use: %v", url, err)
+		return true
+	}
+	return count > 0
+}
+
+// deletePostImages removes a purged post's images from Cloudinary, except
+// those other posts still use: translations and copies often share images.
+// Failures are only logged: the post itself is already gone.
+func deletePostImages(urls []string) {
+	seen := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		if seen[url] {
+			continue
+		}
+		seen[url] = true
+		if imageInUse(url) {
+			continue
+		}
+		if err := utils.DeleteFromCloudinary(url); err != nil {
+			log.Printf("Error deleting image %s: %v", url, err)
+		}
+	}
+}
+
+// permanentlyDeletePost purges a post and then its images
+func permanentlyDeletePost(post *models.Post) error {
+	urls := postImageURLs(post)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return purgePost(tx, post)
+	})
+	if err != nil {
+		return err
+	}
+	deletePostImages(urls)
+	return nil
+}
+
+// PurgeTrashedPosts permanently deletes posts that have been in the trash
+// longer than the configured retention period. It returns how many were purged.
+func PurgeTrashedPosts() int {
+	days := config.AppConfig.TrashRetentionDays
+	if database.DB == nil || days <= 0 {
+		return 0
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	// Posts trashed before TrashedAt existed fall back to their last update
+	var posts []models.Post
+	err := database.DB.Unscoped().
+		Where("status = ?", models.PostStatusTrash).
+		Where("COALESCE(trashed_at, updated_at) < ?", cutoff).
+		Find(&posts).Error
+	if err != nil {
+		log.Println("Error loading trashed posts:", err)
+		return 0
+	}
+
+	purged := 0
+	for i := range posts {
+		if err := permanentlyDeletePost(&posts[i]); err != nil {
+			log.Printf("Error purging post %s: %v", posts[i].ID, err)
+			continue
+		}
+		purged++
+	}
+	if purged > 0 {
+		log.Printf("✓ Purged %d trashed posts older than %d days", purged, days)
+	}
+	return purged
+}
+
+// StartTrashPurger runs PurgeTrashedPosts now and then once a day
+func StartTrashPurger() {
+	go func() {
+		PurgeTrashedPosts()
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			PurgeTrashedPosts()
+		}
+	}()
+}
+
+// restoreTarget is the status a trashed post goes back to: the one it had
+// before. If the workflow doesn't allow that any more (e.g. publishing now
+// needs review), it falls back to draft.
+func restoreTarget(post *models.Post, actor workflowActor) string {
+	target := post.PreviousStatus
+	if target == "" || checkPostTransition(post.Status, target, actor) != nil {
+		target = models.PostStatusDraft
+	}
+	return target
+}
+
+// RestorePost is the handler for POST /api/posts/:id/restore
+// Moves a trashed post back to the status it had before it was trashed.
+func RestorePost(c *fiber.Ctx) error {
+	// 1. Find the post
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if post.Status != models.PostStatusTrash {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status": "error", "message": "Post is not in the trash",
+		})
+	}
+
+	// 2. Authorization Check
+	actor := actorFor(c, post)
+	if !actor.Owner && !isAdmin(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "You are not authorized to restore this post",
+		})
+	}
+	if ok, err := checkIfMatch(c, post); !ok {
+		return err
+	}
+
+	// 3. Go back to the remembered status
+	applyPostStatus(post, restoreTarget(post, actor))
+	post.UpdatedAt = time.Now()
+
+	if err := savePost(database.DB, post); err != nil {
+		if errors.Is(err, errStalePost) {
+			return stalePostResponse(c, post)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to restore post", "error": err.Error(),
+		})
+	}
+	invalidateRelatedPosts()
+
+	database.DB.Preload("Author").Preload("Tags").First(post, post.ID)
+	decoratePost(c, post)
+
+	c.Set(fiber.HeaderETag, postETag(post))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post restored successfully",
+		"data":    post,
+	})
+}
+
+// PurgeTrash is the handler for POST /api/admin/trash/purge (admins only)
+// Runs the retention purge on demand, e.g. from a scheduled job.
+func PurgeTrash(c *fiber.Ctx) error {
+	if !isAdmin(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error", "message": "Admin access required",
+		})
+	}
+
+	return purgeTrashResponse(c)
+}
+
+// CronPurgeTrash is the handler for GET /api/cron/purge-trash
+// The retention purge for serverless deployments, where no background
+// ticker runs: Vercel Cron calls it with "Authorization: Bearer <CRON_SECRET>".
+// It is disabled while CRON_SECRET is unset.
+func CronPurgeTrash(c *fiber.Ctx) error {
+	secret := config.AppConfig.CronSecret
+	token := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Invalid cron secret",
+		})
+	}
+	return purgeTrashResponse(c)
+}
+
+// purgeTrashResponse runs the purge and reports how many posts went
+func purgeTrashResponse(c *fiber.Ctx) error {
+	purged := PurgeTrashedPosts()
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Trash purged successfully",
+		"data": fiber.Map{
+			"purged":         purged,
+			"retention_days": config.AppConfig.TrashRetentionDays,
+		},
+	})
+}
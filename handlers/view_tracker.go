@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/database"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// botUserAgent matches crawlers, link previewers and scripted clients
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|preview|facebookexternalhit|headless|lighthouse|curl|wget|python-requests|go-http-client|okhttp`)
+
+type viewKey struct {
+	PostID uuid.UUID
+	Day    string // YYYY-MM-DD (UTC)
+}
+
+type referrerKey struct {
+	viewKey
+	Referrer string
+}
+
+// viewTracker buffers post views in memory so reading a post doesn't turn
+// into a database write; StartViewFlusher writes the counts in batches.
+//
+// Without a background flusher there is no buffering: every counted view is
+// written during the request that read the post. This is the case on
+// serverless, where an instance may be frozen or dropped between requests
+// and anything left in memory would be lost. It costs one small transaction
+// per counted view on the read path, which we accept over undercounting.
+type viewTracker struct {
+	mu        sync.Mutex
+	buffered  bool                 // StartViewFlusher is running
+	seen      map[string]time.Time // visitor+post -> when the view was last counted
+	views     map[viewKey]int64
+	referrers map[referrerKey]int64
+	pending   int
+}
+
+var views = &viewTracker{
+	seen:      map[string]time.Time{},
+	views:     map[viewKey]int64{},
+	referrers: map[referrerKey]int64{},
+}
+
+// recordView counts a view of the post unless it comes from a bot, from the
+// post's own author, or from a visitor already counted within the dedup window.
+func recordView(c *fiber.Ctx, post *models.Post) {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if userAgent == "" || botUserAgent.MatchString(userAgent) {
+		return
+	}
+	if userID, ok := currentUserID(c); ok && userID == post.AuthorID {
+		return
+	}
+
+	now := time.Now().UTC()
+	visitor := visitorKey(c, userAgent, post.ID)
+	window := time.Duration(config.AppConfig.ViewDedupMinutes) * time.Minute
+	key := viewKey{PostID: post.ID, Day: now.Format("2006-01-02")}
+	referrer := referrerHost(c)
+
+	views.mu.Lock()
+	if last, ok := views.seen[visitor]; ok && now.Sub(last) < window {
+		views.mu.Unlock()
+		return
+	}
+	views.seen[visitor] = now
+	views.views[key]++
+	views.referrers[referrerKey{viewKey: key, Referrer: referrer}]++
+	views.pending++
+	full := views.pending >= config.AppConfig.ViewFlushBatchSize
+	buffered := views.buffered
+	views.mu.Unlock()
+
+	switch {
+	case !buffered:
+		FlushViews()
+		pruneSeenVisitors()
+	case full:
+		go FlushViews()
+	}
+}
+
+// visitorKey identifies a visitor without storing their IP address.
+// Authenticated users are identified by their account instead. c.IP() only
+// reads the proxy header from trusted proxies (PROXY_HEADER, TRUSTED_PROXIES).
+func visitorKey(c *fiber.Ctx, userAgent string, postID uuid.UUID) string {
+	identity := c.IP() + "|" + userAgent
+	if userID, ok := currentUserID(c); ok {
+		identity = "user|" + userID.String()
+	}
+	sum := sha256.Sum256([]byte(identity + "|" + postID.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// referrerHost reduces the Referer header to its host, or "direct"
+func referrerHost(c *fiber.Ctx) string {
+	parsed, err := url.Parse(c.Get(fiber.HeaderReferer))
+	if err != nil || parsed.Host == "" {
+		return "direct"
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if len(host) > 255 {
+		host = host[:255]
+	}
+	return host
+}
+
+// FlushViews writes the buffered views to the database.
+// Counts that fail to write are put back into the buffer for the next flush.
+func FlushViews() {
+	if database.DB == nil {
+		return
+	}
+
+	// Swap the buffers so recording never waits on the database
+	views.mu.Lock()
+	pendingViews, pendingReferrers := views.views, views.referrers
+	views.views, views.referrers = map[viewKey]int64{}, map[referrerKey]int64{}
+	views.pending = 0
+	views.mu.Unlock()
+
+	if len(pendingViews) == 0 {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		perPost := map[uuid.UUID]int64{}
+		for key, count := range pendingViews {
+			day, _ := time.Parse("2006-01-02", key.Day)
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "post_id"}, {Name: "date"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("post_view_stats.views + EXCLUDED.views")}),
+			}).Create(&models.PostViewStat{PostID: key.PostID, Date: day, Views: count}).Error
+			if err != nil {
+				return err
+			}
+			perPost[key.PostID] += count
+		}
+
+		for key, count := range pendingReferrers {
+			day, _ := time.Parse("2006-01-02", key.Day)
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "post_id"}, {Name: "date"}, {Name: "referrer"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("post_referrer_stats.views + EXCLUDED.views")}),
+			}).Create(&models.PostReferrerStat{PostID: key.PostID, Date: day, Referrer: key.Referrer, Views: count}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for postID, count := range perPost {
+			err := tx.Model(&models.Post{}).Unscoped().Where("id = ?", postID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Println("Error flushing post views:", err)
+		views.mu.Lock()
+		for key, count := range pendingViews {
+			views.views[key] += count
+		}
+		for key, count := range pendingReferrers {
+			views.referrers[key] += count
+		}
+		views.mu.Unlock()
+	}
+}
+
+// pruneSeenVisitors forgets visitors whose dedup window has passed
+func pruneSeenVisitors() {
+	window := time.Duration(config.AppConfig.ViewDedupMinutes) * time.Minute
+	views.mu.Lock()
+	defer views.mu.Unlock()
+	for visitor, last := range views.seen {
+		if time.Since(last) >= window {
+			delete(views.seen, visitor)
+		}
+	}
+}
+
+// StartViewFlusher periodically flushes buffered views in the background
+func StartViewFlusher() {
+	interval := time.Duration(config.AppConfig.ViewFlushSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	views.mu.Lock()
+	views.buffered = true
+	views.mu.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			FlushViews()
+			pruneSeenVisitors()
+		}
+	}()
+}
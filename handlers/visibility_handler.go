@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	postAccessAudience = "post-access"
+	postAccessTTL      = time.Hour
+
+	// postAccessHeader carries an access token from POST /api/posts/:id/unlock.
+	// Same-site clients may rely on the cookie set by that endpoint instead.
+	postAccessHeader       = "X-Post-Access"
+	postAccessCookiePrefix = "post_access_"
+)
+
+// UnlockPostRequest is the struct for unlocking a password-protected post
+type UnlockPostRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// postAccessKey signs access tokens, derived from the JWT secret like previewKey
+func postAccessKey() []byte {
+	sum := sha256.Sum256([]byte(config.AppConfig.JWTSecret + "|" + postAccessAudience))
+	return sum[:]
+}
+
+// passwordFingerprint identifies the post's current password in its access
+// tokens, so changing the password revokes every token issued before
+func passwordFingerprint(post *models.Post) string {
+	sum := sha256.Sum256([]byte(post.PasswordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// applyPostVisibility sets the post's visibility and password from a
+// request. An empty visibility keeps the current one, and an empty password
+// keeps the current password. Returns a message when the combination is invalid.
+func applyPostVisibility(post *models.Post, visibility, password string) string {
+	if visibility == "" {
+		visibility = post.Visibility
+	}
+	if visibility == "" {
+		visibility = models.PostVisibilityPublic
+	}
+
+	if visibility != models.PostVisibilityPassword {
+		if password != "" {
+			return "A password can only be set on password-protected posts"
+		}
+		post.Visibility = visibility
+		post.PasswordHash = ""
+		return ""
+	}
+
+	if password == "" {
+		if post.PasswordHash == "" {
+			return "Password-protected posts need a password"
+		}
+		post.Visibility = visibility
+		return ""
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return "Failed to hash password"
+	}
+	post.Visibility = visibility
+	post.PasswordHash = hash
+	return ""
+}
+
+// hasPostAccessToken reports whether the request carries a valid access
+// token for the password-protected post
+func hasPostAccessToken(c *fiber.Ctx, post *models.Post) bool {
+	raw := c.Get(postAccessHeader)
+	if raw == "" {
+		raw = c.Cookies(postAccessCookiePrefix + post.ID.String())
+	}
+	if raw == "" {
+		return false
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(*jwt.Token) (interface{}, error) {
+		return postAccessKey(), nil
+	}, jwt.WithAudience(postAccessAudience), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	return err == nil && claims.Subject == post.ID.String() && claims.ID == passwordFingerprint(post)
+}
+
+// lockPost withholds the content of a post the caller may not read in full
+func lockPost(post *models.Post) {
+	post.Content = ""
+	post.ContentHTML = ""
+	post.TOC = nil
+	post.Locked = true
+}
+
+// attachAccess locks the posts whose content the caller may not read:
+// members-only posts for anonymous callers, and password-protected posts
+// without a valid access token. Authors, co-authors and admins always read
+// them, and so does the holder of a preview link for the post.
+func attachAccess(c *fiber.Ctx, posts []models.Post) {
+	userID, signedIn := currentUserID(c)
+	admin := isAdmin(c)
+	previewed, _ := c.Locals(previewPostKey).(uuid.UUID)
+
+	protected := map[uuid.UUID]bool{}
+	for i := range posts {
+		post := &posts[i]
+		switch {
+		case post.ID == previewed:
+			continue
+		case post.Visibility == models.PostVisibilityMembers && !signedIn:
+			lockPost(post)
+		case post.Visibility == models.PostVisibilityPassword:
+			if admin || (signedIn && post.AuthorID == userID) || hasPostAccessToken(c, post) {
+				continue
+			}
+			protected[post.ID] = true
+		}
+	}
+	if len(protected) == 0 {
+		return
+	}
+
+	// Co-authors may read the remaining posts; look them up in one query
+	if signedIn {
+		ids := make([]uuid.UUID, 0, len(protected))
+		for id := range protected {
+			ids = append(ids, id)
+		}
+		for id := range coAuthoredPosts(userID, ids) {
+			delete(protected, id)
+		}
+	}
+	for i := range posts {
+		if protected[posts[i].ID] {
+			lockPost(&posts[i])
+		}
+	}
+}
+
+// canReadContent reports whether the caller may read the post's content,
+// and so its comments and reactions, by the same rules as attachAccess
+func canReadContent(c *fiber.Ctx, post *models.Post) bool {
+	posts := []models.Post{*post}
+	attachAccess(c, posts)
+	return !posts[0].Locked
+}
+
+// postLocked is the response for comments and reactions on a post whose
+// content the caller may not read
+func postLocked(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status": "error", "message": "Sign in or unlock the post to see its comments and reactions", "locked": true,
+	})
+}
+
+// UnlockPost is the handler for POST /api/posts/:id/unlock
+// Exchanges the password of a password-protected post for a short-lived
+// access token, returned in the body and as a cookie. Send it back in the
+// X-Post-Access header (or the cookie) to read the post.
+func UnlockPost(c *fiber.Ctx) error {
+	// 1. Find the post
+	post, err := findPostByParam(c)
+	if post == nil {
+		return err
+	}
+	if !canReadPost(c, post) {
+		return postNotFound(c)
+	}
+	if post.Visibility != models.PostVisibilityPassword {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "This post is not password-protected",
+		})
+	}
+
+	// 2. Check the password
+	req := new(UnlockPostRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Invalid request body", "error": err.Error(),
+		})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status": "error", "message": "Validation failed", "error": err.Error(),
+		})
+	}
+	if !checkPasswordHash(req.Password, post.PasswordHash) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error", "message": "Incorrect password",
+		})
+	}
+
+	// 3. Issue the access token
+	expiresAt := time.Now().Add(postAccessTTL)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ID:        passwordFingerprint(post),
+		Subject:   post.ID.String(),
+		Audience:  jwt.ClaimStrings{postAccessAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}).SignedString(postAccessKey())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to unlock post", "error": err.Error(),
+		})
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     postAccessCookiePrefix + post.ID.String(),
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Post unlocked successfully",
+		"data": fiber.Map{
+			"access_token": token,
+			"expires_at":   expiresAt,
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+)
+
+func TestApplyPostVisibility(t *testing.T) {
+	protected := models.Post{Visibility: models.PostVisibilityPassword, PasswordHash: "existing-hash"}
+
+	tests := []struct {
+		name           string
+		post           models.Post
+		visibility     string
+		password       string
+		wantErr        bool
+		wantVisibility string
+		wantHash       string // "new" for a freshly hashed password
+	}{
+		{name: "defaults to public", post: models.Post{}, wantVisibility: models.PostVisibilityPublic},
+		{name: "keeps the current visibility", post: models.Post{Visibility: models.PostVisibilityMembers}, wantVisibility: models.PostVisibilityMembers},
+		{name: "unlisted", post: models.Post{}, visibility: models.PostVisibilityUnlisted, wantVisibility: models.PostVisibilityUnlisted},
+		{name: "password on a public post", post: models.Post{}, visibility: models.PostVisibilityPublic, password: "secret", wantErr: true},
+		{name: "password-protected without a password", post: models.Post{}, visibility: models.PostVisibilityPassword, wantErr: true},
+		{name: "password-protected", post: models.Post{}, visibility: models.PostVisibilityPassword, password: "secret", wantVisibility: models.PostVisibilityPassword, wantHash: "new"},
+		{name: "keeps the current password", post: protected, wantVisibility: models.PostVisibilityPassword, wantHash: "existing-hash"},
+		{name: "changes the password", post: protected, password: "another", wantVisibility: models.PostVisibilityPassword, wantHash: "new"},
+		{name: "making it public drops the password", post: protected, visibility: models.PostVisibilityPublic, wantVisibility: models.PostVisibilityPublic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := tt.post
+			msg := applyPostVisibility(&post, tt.visibility, tt.password)
+			if tt.wantErr {
+				if msg == "" {
+					t.Errorf("applyPostVisibility(%q, %q) succeeded, want an error", tt.visibility, tt.password)
+				}
+				return
+			}
+			if msg != "" {
+				t.Fatalf("applyPostVisibility(%q, %q) = %q", tt.visibility, tt.password, msg)
+			}
+
+			if post.Visibility != tt.wantVisibility {
+				t.Errorf("visibility = %q, want %q", post.Visibility, tt.wantVisibility)
+			}
+			switch tt.wantHash {
+			case "new":
+				if !checkPasswordHash(tt.password, post.PasswordHash) {
+					t.Errorf("the password hash doesn't match %q", tt.password)
+				}
+			default:
+				if post.PasswordHash != tt.wantHash {
+					t.Errorf("password hash = %q, want %q", post.PasswordHash, tt.wantHash)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 
+	"github.com/mohamadsolkhannawawi/article-backend/config"
 	"github.com/mohamadsolkhannawawi/article-backend/database"
 	"github.com/mohamadsolkhannawawi/article-backend/handlers"
 	"github.com/mohamadsolkhannawawi/article-backend/middleware"
@@ -16,11 +17,26 @@ import (
 
 func runMigrations(db *gorm.DB) {
 	log.Println("Running Migrations...")
-	err := db.AutoMigrate(&models.User{}, &models.Tag{}, &models.Post{})
+	err := db.AutoMigrate(&models.User{}, &models.Tag{}, &models.Post{}, &models.Comment{}, &models.PostReaction{}, &models.PostViewStat{}, &models.PostReferrerStat{}, &models.Bookmark{}, &models.ReadingList{}, &models.ReadingListItem{}, &models.PostReview{}, &models.Category{}, &models.PostAuthor{}, &models.Series{}, &models.SeriesPost{})
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 	log.Println("Database Migrated Successfully!")
+
+	// Backfill the cached rendered HTML for posts created before rendering existed
+	handlers.RenderMissingContent(db)
+
+	// Move free-text categories into the categories table
+	handlers.MigrateCategories(db)
+
+	// Merge tags that only differ in case or whitespace
+	handlers.NormalizeTags(db)
+
+	// Trigram similarity for related posts
+	handlers.EnableTextSimilarity(db)
+
+	// Posts written before locales existed are in the default locale
+	handlers.BackfillPostLocales(db)
 }
 
 func setupRoutes(app *fiber.App) {
@@ -31,19 +47,110 @@ func setupRoutes(app *fiber.App) {
 	api.Post("/login", handlers.LoginUser)
 
 	// --- Public Post Routes ---
-	api.Get("/posts", handlers.GetPosts)
+	api.Get("/posts", middleware.OptionalAuth(), handlers.GetPosts)
 	// ⭐ IMPORTANT: /posts/my MUST come BEFORE /posts/:id
 	// Otherwise /posts/my will be caught by /posts/:id route (my treated as ID parameter)
 	api.Get("/posts/my", middleware.AuthRequired(), handlers.GetMyPosts)
-	api.Get("/posts/:id", handlers.GetPostByID)
+	api.Get("/posts/featured", middleware.OptionalAuth(), handlers.GetFeaturedPosts)
+	api.Get("/posts/:id", middleware.OptionalAuth(), handlers.GetPostByID)
 
 	// --- Protected Post Routes ---
 	api.Post("/posts", middleware.AuthRequired(), handlers.CreatePost)
+	api.Post("/posts/bulk", middleware.AuthRequired(), handlers.BulkUpdatePosts)
 	api.Put("/posts/:id", middleware.AuthRequired(), handlers.UpdatePost)
+	api.Patch("/posts/:id", middleware.AuthRequired(), handlers.PatchPost)
 	api.Delete("/posts/:id", middleware.AuthRequired(), handlers.DeletePost)
 
+	// --- Comment Routes ---
+	api.Get("/posts/:id/comments", middleware.OptionalAuth(), handlers.GetComments)
+	api.Post("/posts/:id/comments", middleware.AuthRequired(), handlers.CreateComment)
+	api.Put("/posts/:id/comment-settings", middleware.AuthRequired(), handlers.UpdateCommentSettings)
+	api.Get("/comments/moderation", middleware.AuthRequired(), handlers.GetModerationQueue)
+	api.Put("/comments/:id", middleware.AuthRequired(), handlers.UpdateComment)
+	api.Delete("/comments/:id", middleware.AuthRequired(), handlers.DeleteComment)
+	api.Post("/comments/:id/moderate", middleware.AuthRequired(), handlers.ModerateComment)
+
+	// --- Reaction Routes ---
+	api.Get("/posts/:id/reactions", middleware.OptionalAuth(), handlers.GetReactions)
+	api.Post("/posts/:id/reactions", middleware.AuthRequired(), handlers.ToggleReaction)
+
+	// --- Related Posts Routes ---
+	api.Get("/posts/:id/related", middleware.OptionalAuth(), handlers.GetRelatedPosts)
+
+	// --- Preview Routes ---
+	api.Post("/posts/:id/preview-link", middleware.AuthRequired(), handlers.CreatePreviewLink)
+	api.Get("/preview/:token", handlers.GetPostPreview)
+
+	// --- Visibility Routes ---
+	api.Post("/posts/:id/unlock", middleware.UnlockLimiter(), middleware.OptionalAuth(), handlers.UnlockPost)
+
+	// --- Translation Routes ---
+	api.Post("/posts/:id/translations", middleware.AuthRequired(), handlers.LinkTranslation)
+	api.Delete("/posts/:id/translations", middleware.AuthRequired(), handlers.UnlinkTranslation)
+
+	// --- Co-author Routes ---
+	api.Get("/posts/:id/authors", middleware.OptionalAuth(), handlers.GetPostAuthors)
+	api.Put("/posts/:id/authors", middleware.AuthRequired(), handlers.UpdatePostAuthors)
+
+	// --- Series Routes ---
+	api.Get("/series", handlers.GetSeriesList)
+	api.Get("/series/:slug", middleware.OptionalAuth(), handlers.GetSeries)
+	api.Post("/series", middleware.AuthRequired(), handlers.CreateSeries)
+	api.Put("/series/:id", middleware.AuthRequired(), handlers.UpdateSeries)
+	api.Delete("/series/:id", middleware.AuthRequired(), handlers.DeleteSeries)
+	api.Put("/series/:id/posts", middleware.AuthRequired(), handlers.SetSeriesPosts)
+
+	// --- SEO Routes ---
+	api.Get("/posts/:id/meta", middleware.OptionalAuth(), handlers.GetPostMeta)
+
+	// --- Analytics Routes ---
+	api.Get("/posts/:id/stats", middleware.AuthRequired(), handlers.GetPostStats)
+
+	// --- Bookmark & Reading List Routes ---
+	api.Post("/posts/:id/bookmark", middleware.AuthRequired(), handlers.AddBookmark)
+	api.Delete("/posts/:id/bookmark", middleware.AuthRequired(), handlers.RemoveBookmark)
+	api.Get("/bookmarks", middleware.AuthRequired(), handlers.GetBookmarks)
+	api.Get("/reading-lists", middleware.AuthRequired(), handlers.GetReadingLists)
+	api.Post("/reading-lists", middleware.AuthRequired(), handlers.CreateReadingList)
+	api.Get("/reading-lists/:id", middleware.AuthRequired(), handlers.GetReadingList)
+	api.Put("/reading-lists/:id", middleware.AuthRequired(), handlers.UpdateReadingList)
+	api.Delete("/reading-lists/:id", middleware.AuthRequired(), handlers.DeleteReadingList)
+	api.Post("/reading-lists/:id/posts", middleware.AuthRequired(), handlers.AddReadingListItem)
+	api.Delete("/reading-lists/:id/posts/:postId", middleware.AuthRequired(), handlers.RemoveReadingListItem)
+	api.Get("/shared/reading-lists/:token", middleware.OptionalAuth(), handlers.GetSharedReadingList)
+
+	// --- Editorial Review Routes ---
+	api.Post("/posts/:id/submit", middleware.AuthRequired(), handlers.SubmitPostForReview)
+	api.Post("/posts/:id/approve", middleware.AuthRequired(), handlers.ApprovePost)
+	api.Post("/posts/:id/request-changes", middleware.AuthRequired(), handlers.RequestPostChanges)
+	api.Get("/posts/:id/reviews", middleware.AuthRequired(), handlers.GetPostReviews)
+
+	// --- Trash Routes ---
+	api.Post("/posts/:id/restore", middleware.AuthRequired(), handlers.RestorePost)
+	api.Post("/admin/trash/purge", middleware.AuthRequired(), handlers.PurgeTrash)
+	api.Get("/cron/purge-trash", handlers.CronPurgeTrash)
+
+	// --- Category Routes ---
+	api.Get("/categories", handlers.GetCategories)
+	api.Get("/categories/:slug", handlers.GetCategory)
+	api.Get("/categories/:slug/posts", middleware.OptionalAuth(), handlers.GetCategoryPosts)
+	api.Post("/categories", middleware.AuthRequired(), handlers.CreateCategory)
+	api.Put("/categories/:id", middleware.AuthRequired(), handlers.UpdateCategory)
+	api.Delete("/categories/:id", middleware.AuthRequired(), handlers.DeleteCategory)
+
+	// --- Tag Routes ---
+	api.Get("/tags", handlers.GetTags)
+	api.Get("/tags/:name/posts", middleware.OptionalAuth(), handlers.GetTagPosts)
+	api.Delete("/tags/unused", middleware.AuthRequired(), handlers.DeleteUnusedTags)
+	api.Put("/tags/:id", middleware.AuthRequired(), handlers.RenameTag)
+	api.Post("/tags/:id/merge", middleware.AuthRequired(), handlers.MergeTag)
+
 	// --- Protected Admin Routes ---
 	api.Get("/admin/posts", middleware.AuthRequired(), handlers.GetAdminPosts)
+	api.Put("/admin/posts/:id/pin", middleware.AuthRequired(), handlers.PinPost)
+	api.Delete("/admin/posts/:id/pin", middleware.AuthRequired(), handlers.UnpinPost)
+	api.Put("/admin/posts/:id/feature", middleware.AuthRequired(), handlers.FeaturePost)
+	api.Delete("/admin/posts/:id/feature", middleware.AuthRequired(), handlers.UnfeaturePost)
 	api.Post("/upload", middleware.AuthRequired(), handlers.UploadImage)
 
 	// --- Protected User Routes ---
@@ -58,6 +165,17 @@ func setupRoutes(app *fiber.App) {
 		})
 	})
 
+	// --- Feeds ---
+	// ?category=<slug>, ?tag=<name> and ?author=<id> select a narrower feed
+	app.Get("/feed.xml", handlers.GetRSSFeed)
+	app.Get("/atom.xml", handlers.GetAtomFeed)
+	app.Get("/feed.json", handlers.GetJSONFeed)
+
+	// --- Sitemap & Robots ---
+	app.Get("/sitemap.xml", handlers.GetSitemap)
+	app.Get("/sitemaps/:page.xml", handlers.GetSitemapPage)
+	app.Get("/robots.txt", handlers.GetRobots)
+
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"message": "Welcome to KataGenzi API!",
@@ -89,14 +207,27 @@ func main() {
 	// Run migrations
 	runMigrations(database.DB)
 
+	// Flush buffered post views in the background
+	handlers.StartViewFlusher()
+
+	// Purge posts that stayed in the trash past the retention period
+	handlers.StartTrashPurger()
+
 	// Create Fiber app
-	app := fiber.New()
+	// c.IP() only believes the proxy header from trusted proxies
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             config.AppConfig.ProxyHeader,
+		EnableTrustedProxyCheck: len(config.AppConfig.TrustedProxies) > 0,
+		TrustedProxies:          config.AppConfig.TrustedProxies,
+		EnableIPValidation:      true,
+	})
 
 	// CORS
 	app.Use(func(c *fiber.Ctx) error {
 		c.Set("Access-Control-Allow-Origin", "*")
-		c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, If-Match, If-None-Match, X-Post-Access")
+		c.Set("Access-Control-Expose-Headers", "ETag, Link")
 
 		if c.Method() == "OPTIONS" {
 			return c.SendStatus(fiber.StatusNoContent)
@@ -15,6 +15,66 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// parseBearerToken validates the "Bearer <token>" Authorization header and
+// returns its claims. On failure it returns nil claims together with the
+// status code and error response to send.
+func parseBearerToken(authHeader string) (*handlers.JwtCustomClaims, int, fiber.Map) {
+	// 1. Token is usually sent in the format "Bearer <token>"
+	// We need to separate "Bearer" from the token itself
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, fiber.StatusUnauthorized, fiber.Map{
+			"status":  "error",
+			"message": "Invalid authorization header format",
+		}
+	}
+
+	tokenString := parts[1]
+
+	// 2. Get JWT_SECRET from config
+	jwtSecret := config.AppConfig.JWTSecret
+	if jwtSecret == "" {
+		log.Println("Warning: JWT_SECRET is not set")
+		return nil, fiber.StatusInternalServerError, fiber.Map{
+			"status":  "error",
+			"message": "Server configuration error",
+		}
+	}
+
+	// 3. Parse and validate token
+	claims := &handlers.JwtCustomClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Make sure the signing method is HS256
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fiber.ErrUnauthorized
+		}
+		return []byte(jwtSecret), nil
+	})
+
+	if err != nil || !token.Valid {
+		// This can happen if the token is expired or invalid
+		if err == nil {
+			err = fiber.ErrUnauthorized
+		}
+		return nil, fiber.StatusUnauthorized, fiber.Map{
+			"status":  "error",
+			"message": "Invalid or expired token",
+			"error":   err.Error(),
+		}
+	}
+
+	return claims, fiber.StatusOK, nil
+}
+
+// setUserLocals stores user info from the token into Fiber's context
+// so it can be accessed by subsequent handlers.
+func setUserLocals(c *fiber.Ctx, claims *handlers.JwtCustomClaims) {
+	c.Locals("userID", claims.UserID)
+	c.Locals("userEmail", claims.Email)
+	c.Locals("userFullName", claims.FullName)
+	c.Locals("userRole", claims.Role)
+}
+
 // AuthRequired is a middleware to protect routes that require authentication
 func AuthRequired() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -27,56 +87,30 @@ func AuthRequired() fiber.Handler {
 			})
 		}
 
-		// 2. Token is usually sent in the format "Bearer <token>"
-		// We need to separate "Bearer" from the token itself
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Invalid authorization header format",
-			})
+		// 2. Parse and validate the token
+		claims, status, errResponse := parseBearerToken(authHeader)
+		if claims == nil {
+			return c.Status(status).JSON(errResponse)
 		}
 
-		tokenString := parts[1]
+		// 3. Token valid!
+		setUserLocals(c, claims)
 
-		// 3. Get JWT_SECRET from .env
-		// jwtSecret := os.Getenv("JWT_SECRET")
-		jwtSecret := config.AppConfig.JWTSecret
-		if jwtSecret == "" {
-			log.Println("Warning: JWT_SECRET is not set")
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Server configuration error",
-			})
-		}
+		// Proceed to the next handler (endpoint)
+		return c.Next()
+	}
+}
 
-		// 4. Parse and validate token
-		claims := &handlers.JwtCustomClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			// Make sure the signing method is HS256
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fiber.ErrUnauthorized
+// OptionalAuth is the lenient variant of AuthRequired for public routes.
+// A valid token populates the same locals; a missing or invalid token
+// simply leaves the request anonymous instead of rejecting it.
+func OptionalAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if authHeader := c.Get("Authorization"); authHeader != "" {
+			if claims, _, _ := parseBearerToken(authHeader); claims != nil {
+				setUserLocals(c, claims)
 			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			// This can happen if the token is expired or invalid
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Invalid or expired token",
-				"error":   err.Error(),
-			})
 		}
-
-		// 5. Token valid!
-		// We store user info from the token into Fiber's context
-		// so it can be accessed by subsequent handlers.
-		c.Locals("userID", claims.UserID)
-		c.Locals("userEmail", claims.Email)
-		c.Locals("userFullName", claims.FullName)
-
-		// Proceed to the next handler (endpoint)
 		return c.Next()
 	}
 }
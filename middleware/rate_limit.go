@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/mohamadsolkhannawawi/article-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// UnlockLimiter limits password attempts on a protected post per client IP
+// and post. Only failed attempts count, so readers who get the password
+// right are never held back. The counts live in memory, per instance.
+func UnlockLimiter() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        config.AppConfig.UnlockMaxAttempts,
+		Expiration: time.Duration(config.AppConfig.UnlockWindowMinutes) * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP() + "|" + c.Params("id")
+		},
+		SkipSuccessfulRequests: true,
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Too many attempts, please try again later",
+			})
+		},
+	})
+}
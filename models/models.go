@@ -7,12 +7,45 @@ import (
 	"gorm.io/gorm"
 )
 
+// Supported values for Post.ContentFormat
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+	ContentFormatPlain    = "plain"
+)
+
+// Supported values for User.Role
+const (
+	RoleAuthor = "author"
+	RoleEditor = "editor" // May review, approve and publish other authors' posts
+	RoleAdmin  = "admin"
+)
+
+// Supported values for Post.Status.
+// Allowed moves between them live in the handlers' post workflow.
+const (
+	PostStatusDraft         = "draft"
+	PostStatusPendingReview = "pending_review"
+	PostStatusPublish       = "publish"
+	PostStatusTrash         = "trash"
+)
+
+// Supported values for Post.Visibility. Visibility only applies to
+// published posts; Status decides whether a post is published at all.
+const (
+	PostVisibilityPublic   = "public"
+	PostVisibilityUnlisted = "unlisted" // Readable by link, left out of listings and feeds
+	PostVisibilityMembers  = "members"  // Content requires any signed-in user
+	PostVisibilityPassword = "password" // Content requires the post's password
+)
+
 // 1. User Model
 type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	FullName     string    `gorm:"size:100;not null" json:"full_name"`
 	Email        string    `gorm:"size:255;not null;unique" json:"email"`
 	PasswordHash string    `gorm:"size:255;not null" json:"-"` // Exclude from JSON responses
+	Role         string    `gorm:"size:20;not null;default:'author'" json:"role"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
@@ -26,12 +59,18 @@ type Tag struct {
 
 // 3. Post Model
 type Post struct {
-	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Title            string    `gorm:"size:200;not null" json:"title"`
-	Content          string    `gorm:"type:text;not null" json:"content"`
-	Category         string    `gorm:"size:100;not null" json:"category"`
-	Status           string    `gorm:"size:50;not null;default:'draft'" json:"status"`
-	FeaturedImageURL string    `gorm:"type:text" json:"featured_image_url"`
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Title              string    `gorm:"size:200;not null" json:"title"`
+	Content            string    `gorm:"type:text;not null" json:"content,omitempty"`
+	ContentFormat      string    `gorm:"size:20;not null;default:'markdown'" json:"content_format"`
+	ContentHTML        string    `gorm:"type:text" json:"content_html,omitempty"` // Sanitized render of Content, cached on save
+	Summary            string    `gorm:"size:500" json:"summary"`                 // Optional author-provided summary
+	Excerpt            string    `gorm:"type:text" json:"excerpt"`                // Summary, or the first words of the rendered text
+	WordCount          int       `gorm:"not null;default:0" json:"word_count"`
+	ReadingTimeMinutes int       `gorm:"not null;default:0" json:"reading_time_minutes"`
+	Category           string    `gorm:"size:100;not null" json:"category"`
+	Status             string    `gorm:"size:50;not null;default:'draft'" json:"status"`
+	FeaturedImageURL   string    `gorm:"type:text" json:"featured_image_url"`
 
 	// Author Relationship (Many-to-One)
 	AuthorID uuid.UUID `gorm:"not null" json:"author_id"`
@@ -40,6 +79,72 @@ type Post struct {
 	// Tags Relationship (Many-to-Many)
 	Tags []*Tag `gorm:"many2many:post_tags;" json:"tags"`
 
+	// Comment settings
+	CommentsClosed          bool  `gorm:"not null;default:false" json:"comments_closed"`
+	CommentsRequireApproval bool  `gorm:"not null;default:false" json:"comments_require_approval"`
+	CommentCount            int64 `gorm:"-" json:"comment_count"` // Approved comments, filled in by the handlers
+
+	// Reactions, filled in by the handlers
+	Reactions  map[string]int64 `gorm:"-" json:"reactions"`   // Count per reaction type
+	MyReaction *string          `gorm:"-" json:"my_reaction"` // Caller's own reaction, nil if none or anonymous
+	Bookmarked bool             `gorm:"-" json:"bookmarked"`  // Whether the authenticated caller bookmarked the post
+
+	// TOC is the table of contents built from the headings of ContentHTML,
+	// which carry matching anchor IDs. Computed on save like ContentHTML.
+	TOC []TOCEntry `gorm:"type:jsonb;serializer:json" json:"toc,omitempty"`
+
+	// RenderVersion is the version of the renderer that computed the cached
+	// columns above, so posts rendered by an older one can be found and redone
+	RenderVersion int `gorm:"not null;default:0;index" json:"-"`
+
+	// Authors is the full byline: the primary Author first, then the co-authors
+	Authors []PostAuthor `gorm:"-" json:"authors"`
+
+	// SEO overrides set by the author. Empty fields fall back to the title,
+	// excerpt and featured image when the post's metadata is rendered.
+	MetaTitle       string `gorm:"size:200" json:"meta_title"`
+	MetaDescription string `gorm:"size:300" json:"meta_description"`
+	CanonicalURL    string `gorm:"type:text" json:"canonical_url"`
+	SocialImageURL  string `gorm:"type:text" json:"social_image_url"`
+
+	// Series places the post within its series, filled in by GetPostByID
+	Series *SeriesNavigation `gorm:"-" json:"series,omitempty"`
+
+	// Homepage curation, set by admins. A post is pinned to the top of the
+	// listing until PinnedUntil passes; featured posts are ordered by FeaturedRank.
+	PinnedUntil  *time.Time `gorm:"index" json:"pinned_until"`
+	FeaturedRank *int       `gorm:"index" json:"featured_rank"`
+
+	// Who may read the content. Readers without access get the excerpt only,
+	// with Locked set. PasswordHash is the bcrypt hash for password-protected posts.
+	Visibility   string `gorm:"size:20;not null;default:'public'" json:"visibility"`
+	PasswordHash string `gorm:"size:255" json:"-"`
+	Locked       bool   `gorm:"-" json:"locked"`
+
+	// Locale is the post's language. Posts sharing a TranslationGroupID are
+	// translations of each other, with at most one post per locale.
+	Locale             string            `gorm:"size:10;not null;default:'';index;uniqueIndex:idx_posts_translation_locale,priority:2" json:"locale"`
+	TranslationGroupID *uuid.UUID        `gorm:"type:uuid;uniqueIndex:idx_posts_translation_locale,priority:1" json:"translation_group_id"`
+	Translations       []PostTranslation `gorm:"-" json:"translations"` // Published versions of the post (itself included), filled in by the handlers
+
+	// CategoryID references the post's category; Category holds its name
+	CategoryID *uuid.UUID `gorm:"type:uuid;index" json:"category_id"`
+
+	// ViewCount is the all-time number of (deduplicated) views, used for "most viewed" sorting
+	ViewCount int64 `gorm:"not null;default:0;index" json:"view_count"`
+
+	// Trash bookkeeping: the status to restore to, and when the post was trashed
+	PreviousStatus string     `gorm:"size:50" json:"previous_status,omitempty"`
+	TrashedAt      *time.Time `gorm:"index" json:"trashed_at,omitempty"`
+
+	// PublishedAt is set the first time the post is published and is used
+	// (together with ID) as the keyset for cursor pagination
+	PublishedAt *time.Time `gorm:"index" json:"published_at"`
+
+	// Version is bumped on every change and exposed as the post's ETag
+	// for optimistic concurrency (If-Match / If-None-Match)
+	Version int64 `gorm:"not null;default:1" json:"version"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // For soft deletes
@@ -47,3 +152,226 @@ type Post struct {
 
 // We don't need to create a struct for 'post_tags'.
 // GORM will handle it automatically based on the tag `gorm:"many2many:post_tags;"`.
+
+// PostTranslation is one language version of a post, used for hreflang
+// alternates and language switchers
+type PostTranslation struct {
+	Locale string    `json:"hreflang"`
+	PostID uuid.UUID `json:"id"`
+	Title  string    `json:"title"`
+	URL    string    `json:"url"`
+}
+
+// TOCEntry is a heading in a post's table of contents. ID is the anchor
+// of the heading in the rendered HTML; deeper headings nest in Children.
+type TOCEntry struct {
+	ID       string     `json:"id"`
+	Text     string     `json:"text"`
+	Level    int        `json:"level"`
+	Children []TOCEntry `json:"children,omitempty"`
+}
+
+// Supported values for Comment.Status
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+	CommentStatusDeleted  = "deleted" // Placeholder kept so replies stay threaded
+)
+
+// 4. Comment Model
+type Comment struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	PostID uuid.UUID `gorm:"type:uuid;not null;index" json:"post_id"`
+
+	// Author Relationship (Many-to-One)
+	AuthorID uuid.UUID `gorm:"type:uuid;not null;index" json:"author_id"`
+	Author   User      `gorm:"foreignKey:AuthorID" json:"author"`
+
+	// Threading: ParentID is the comment being replied to,
+	// RootID is the top-level comment of the thread (nil for top-level comments)
+	ParentID *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
+	RootID   *uuid.UUID `gorm:"type:uuid;index" json:"root_id"`
+
+	Body     string     `gorm:"type:text;not null" json:"body"`
+	Status   string     `gorm:"size:20;not null;default:'approved';index" json:"status"`
+	EditedAt *time.Time `json:"edited_at"`
+
+	Replies []*Comment `gorm:"-" json:"replies,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ReactionLike is the reaction type that is always available,
+// next to the emoji configured in config.AppConfig.ReactionEmojis
+const ReactionLike = "like"
+
+// 5. PostReaction Model
+// A user has at most one reaction per post (enforced by the unique index).
+type PostReaction struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	PostID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_post_reactions_post_user" json:"post_id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_post_reactions_post_user;index" json:"user_id"`
+	User   User      `gorm:"foreignKey:UserID" json:"user"`
+	Type   string    `gorm:"size:32;not null" json:"type"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 6. PostViewStat Model
+// Daily view totals per post, written in batches by the view tracker.
+type PostViewStat struct {
+	PostID uuid.UUID `gorm:"type:uuid;primaryKey" json:"post_id"`
+	Date   time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Views  int64     `gorm:"not null;default:0" json:"views"`
+}
+
+// 7. PostReferrerStat Model
+// Daily view totals per post and referring host ("direct" when there is none).
+type PostReferrerStat struct {
+	PostID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"post_id"`
+	Date     time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Referrer string    `gorm:"size:255;primaryKey" json:"referrer"`
+	Views    int64     `gorm:"not null;default:0" json:"views"`
+}
+
+// 8. Bookmark Model
+type Bookmark struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bookmarks_user_post" json:"user_id"`
+	PostID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bookmarks_user_post;index" json:"post_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 9. ReadingList Model
+// A named, ordered collection of posts. Public lists are readable by anyone
+// holding the ShareToken.
+type ReadingList struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"user"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	IsPublic    bool      `gorm:"not null;default:false" json:"is_public"`
+	ShareToken  *string   `gorm:"size:64;uniqueIndex" json:"share_token,omitempty"`
+
+	PostCount int64 `gorm:"-" json:"post_count"` // Published posts in the list, filled in by the handlers
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 10. ReadingListItem Model
+type ReadingListItem struct {
+	ReadingListID uuid.UUID `gorm:"type:uuid;primaryKey" json:"reading_list_id"`
+	PostID        uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"post_id"`
+	Position      int       `gorm:"not null;default:0" json:"position"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Supported values for PostReview.Action
+const (
+	ReviewActionSubmit         = "submit"
+	ReviewActionApprove        = "approve"
+	ReviewActionRequestChanges = "request_changes"
+)
+
+// 11. PostReview Model
+// One entry per step of the editorial review workflow.
+type PostReview struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	PostID     uuid.UUID `gorm:"type:uuid;not null;index" json:"post_id"`
+	ReviewerID uuid.UUID `gorm:"type:uuid;not null" json:"reviewer_id"` // Who performed the action (the author for "submit")
+	Reviewer   User      `gorm:"foreignKey:ReviewerID" json:"reviewer"`
+	Action     string    `gorm:"size:30;not null" json:"action"`
+	Comment    string    `gorm:"type:text" json:"comment"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 12. Category Model
+// Categories form a tree through ParentID; siblings are ordered by SortOrder.
+type Category struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name        string     `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Slug        string     `gorm:"size:120;not null;uniqueIndex" json:"slug"`
+	Description string     `gorm:"type:text" json:"description"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
+	SortOrder   int        `gorm:"not null;default:0" json:"sort_order"`
+
+	PostCount int64       `gorm:"-" json:"post_count"`         // Published posts directly in the category
+	Children  []*Category `gorm:"-" json:"children,omitempty"` // Filled in when the tree is requested
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Supported values for PostAuthor.Role
+const (
+	PostAuthorRoleAuthor      = "author"
+	PostAuthorRoleContributor = "contributor"
+	PostAuthorRoleEditor      = "editor"
+)
+
+// 13. PostAuthor Model
+// Co-authors credited on a post, in byline order. The primary author
+// (Post.AuthorID) is not stored here but always comes first in Post.Authors.
+type PostAuthor struct {
+	PostID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"post_id"`
+	UserID   uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	User     User      `gorm:"foreignKey:UserID" json:"user"`
+	Role     string    `gorm:"size:20;not null;default:'author'" json:"role"`
+	Position int       `gorm:"not null;default:0" json:"position"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 14. Series Model
+// An ordered collection of posts, like a multi-part tutorial. The parts
+// are stored in SeriesPost; a post belongs to at most one series.
+type Series struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	AuthorID    uuid.UUID `gorm:"type:uuid;not null;index" json:"author_id"`
+	Author      User      `gorm:"foreignKey:AuthorID" json:"author"`
+	Title       string    `gorm:"size:200;not null" json:"title"`
+	Slug        string    `gorm:"size:220;not null;uniqueIndex" json:"slug"`
+	Description string    `gorm:"type:text" json:"description"`
+
+	PostCount int64 `gorm:"-" json:"post_count"` // Published parts, filled in by the handlers
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 15. SeriesPost Model
+type SeriesPost struct {
+	SeriesID uuid.UUID `gorm:"type:uuid;primaryKey" json:"series_id"`
+	PostID   uuid.UUID `gorm:"type:uuid;primaryKey;uniqueIndex" json:"post_id"`
+	Position int       `gorm:"not null;default:0" json:"position"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SeriesNavigation places a post within its series, filled in by the
+// handlers. Position and Total count the published parts only.
+type SeriesNavigation struct {
+	ID       uuid.UUID       `json:"id"`
+	Title    string          `json:"title"`
+	Slug     string          `json:"slug"`
+	Position int             `json:"position"`
+	Total    int             `json:"total"`
+	Previous *SeriesPostLink `json:"previous"`
+	Next     *SeriesPostLink `json:"next"`
+}
+
+// SeriesPostLink is a previous or next part of a series
+type SeriesPostLink struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
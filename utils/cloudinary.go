@@ -4,6 +4,10 @@ import (
 	"context"
 	"io"
 	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
 
 	"github.com/mohamadsolkhannawawi/article-backend/config"
 
@@ -56,4 +60,44 @@ func UploadToCloudinary(file io.Reader, folder string) (string, error) {
 	}
 
 	return result.SecureURL, nil
-}
\ No newline at end of file
+}
+
+// cloudinaryPublicID extracts the public ID from a Cloudinary delivery URL
+// of our own cloud, e.g. https://res.cloudinary.com/<cloud>/image/upload/v123/folder/name.jpg
+// -> "folder/name". ok is false for any other URL.
+func cloudinaryPublicID(rawURL string) (publicID string, ok bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host != "res.cloudinary.com" {
+		return "", false
+	}
+
+	prefix := "/" + config.AppConfig.CloudinaryCloudName + "/image/upload/"
+	if !strings.HasPrefix(parsed.Path, prefix) {
+		return "", false
+	}
+	publicID = strings.TrimPrefix(parsed.Path, prefix)
+
+	// Drop the optional version segment ("v1712345678/")
+	if parts := strings.SplitN(publicID, "/", 2); len(parts) == 2 && versionSegment.MatchString(parts[0]) {
+		publicID = parts[1]
+	}
+	publicID = strings.TrimSuffix(publicID, path.Ext(publicID))
+	return publicID, publicID != ""
+}
+
+var versionSegment = regexp.MustCompile(`^v\d+$`)
+
+// DeleteFromCloudinary removes an uploaded image given its delivery URL.
+// URLs that don't point to our Cloudinary account are ignored.
+func DeleteFromCloudinary(rawURL string) error {
+	if cld == nil {
+		return nil
+	}
+	publicID, ok := cloudinaryPublicID(rawURL)
+	if !ok {
+		return nil
+	}
+
+	_, err := cld.Upload.Destroy(context.Background(), uploader.DestroyParams{PublicID: publicID})
+	return err
+}
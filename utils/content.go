@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown is the shared Markdown renderer.
+// Raw HTML inside Markdown is NOT rendered (goldmark's default "safe" mode),
+// and the output is sanitized again below anyway.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+)
+
+// sanitizer is the allowlist policy every rendered post goes through
+// before it is stored or served to readers.
+var sanitizer = newSanitizer()
+
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	// Keep fenced code block languages so clients can highlight them
+	p.AllowAttrs("class").Matching(bluemonday.SpaceSeparatedTokens).OnElements("code")
+	return p
+}
+
+// SanitizeHTML strips everything not on the allowlist from an HTML fragment
+func SanitizeHTML(input string) string {
+	return sanitizer.Sanitize(input)
+}
+
+// RenderContent converts post content in the given format into sanitized HTML
+func RenderContent(content, format string) (string, error) {
+	switch format {
+	case models.ContentFormatHTML:
+		return SanitizeHTML(content), nil
+	case models.ContentFormatPlain:
+		return renderPlain(content), nil
+	default:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buf); err != nil {
+			return "", err
+		}
+		return SanitizeHTML(buf.String()), nil
+	}
+}
+
+// renderPlain escapes plain text and turns blank-line separated blocks
+// into paragraphs, keeping single line breaks as <br>.
+func renderPlain(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, block := range strings.Split(content, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		lines := strings.Split(block, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.Join(lines, "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// textExtractor drops every tag and keeps only the text content
+var textExtractor = bluemonday.StrictPolicy()
+
+// PlainText extracts the readable text from an HTML fragment,
+// with all whitespace collapsed to single spaces.
+func PlainText(fragment string) string {
+	// Pad closing tags so words from adjacent blocks don't run together
+	fragment = strings.ReplaceAll(fragment, "</", " </")
+	text := html.UnescapeString(textExtractor.Sanitize(fragment))
+	return strings.Join(strings.Fields(text), " ")
+}
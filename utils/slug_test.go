@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lowercases", "Golang", "golang"},
+		{"joins words with dashes", "Web & Mobile", "web-mobile"},
+		{"collapses separators", "  Go -- Web__Dev  ", "go-web-dev"},
+		{"keeps digits", "Top 10 Tips", "top-10-tips"},
+		{"keeps accented letters", "Café Crème", "café-crème"},
+		{"keeps other scripts", "日本語 ガイド", "日本語-ガイド"},
+		{"trims leading and trailing punctuation", "...Hello, World!", "hello-world"},
+		{"empty", "", ""},
+		{"only punctuation", "&*!?", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.in); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// headingLevels maps heading tags to their level
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// tocNode is a TOCEntry while the tree is being built
+type tocNode struct {
+	entry    models.TOCEntry
+	children []*tocNode
+}
+
+func (n *tocNode) toEntry() models.TOCEntry {
+	entry := n.entry
+	for _, child := range n.children {
+		entry.Children = append(entry.Children, child.toEntry())
+	}
+	return entry
+}
+
+// headingAnchors hands out unique anchor IDs: the slug of the heading
+// text, with -2, -3… appended when it was already used
+type headingAnchors map[string]bool
+
+func (a headingAnchors) next(text string) string {
+	base := Slugify(text)
+	if base == "" {
+		base = "section"
+	}
+	id := base
+	for n := 2; a[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	a[id] = true
+	return id
+}
+
+// AddHeadingAnchors gives every heading of a sanitized HTML fragment an id
+// derived from its text (in any script) and returns the fragment with the
+// ids, plus the headings as a nested table of contents. IDs only depend on
+// the headings, so they stay the same as long as the headings do.
+func AddHeadingAnchors(fragment string) (string, []models.TOCEntry) {
+	var out bytes.Buffer
+	anchors := headingAnchors{}
+	var roots, stack []*tocNode
+
+	// The heading being read: its start tag is held back until its text,
+	// and so its id, is known
+	var current *tocNode
+	var startTag []byte
+	var attrs, pending bytes.Buffer
+	var text strings.Builder
+
+	z := xhtml.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break // End of the fragment
+		}
+		raw := append([]byte(nil), z.Raw()...)
+		name, hasAttr := z.TagName()
+		level, isHeading := headingLevels[string(name)]
+
+		switch {
+		case tt == xhtml.StartTagToken && isHeading && current == nil:
+			current = &tocNode{entry: models.TOCEntry{Level: level}}
+			startTag = raw
+			attrs.Reset()
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if string(key) != "id" { // Replaced by the generated anchor
+					attrs.WriteString(" " + string(key) + `="` + html.EscapeString(string(val)) + `"`)
+				}
+			}
+			pending.Reset()
+			text.Reset()
+			continue
+		case tt == xhtml.EndTagToken && isHeading && current != nil:
+			current.entry.Text = strings.Join(strings.Fields(text.String()), " ")
+			current.entry.ID = anchors.next(current.entry.Text)
+			out.WriteString("<h" + strconv.Itoa(current.entry.Level) + attrs.String() + ` id="` + html.EscapeString(current.entry.ID) + `">`)
+			out.Write(pending.Bytes())
+			out.WriteString("</h" + strconv.Itoa(current.entry.Level) + ">")
+
+			// Attach to the closest preceding heading of a higher level
+			for len(stack) > 0 && stack[len(stack)-1].entry.Level >= current.entry.Level {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				roots = append(roots, current)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, current)
+			}
+			stack = append(stack, current)
+			current = nil
+			continue
+		}
+
+		if current != nil {
+			pending.Write(raw)
+			if tt == xhtml.TextToken {
+				text.WriteString(html.UnescapeString(string(raw)))
+			}
+			continue
+		}
+		out.Write(raw)
+	}
+	if current != nil { // A heading that was never closed is left as it was
+		out.Write(startTag)
+		out.Write(pending.Bytes())
+	}
+
+	toc := make([]models.TOCEntry, 0, len(roots))
+	for _, root := range roots {
+		toc = append(toc, root.toEntry())
+	}
+	return out.String(), toc
+}
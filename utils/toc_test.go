@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mohamadsolkhannawawi/article-backend/models"
+)
+
+func TestAddHeadingAnchors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		wantHTML string
+		wantTOC  []models.TOCEntry
+	}{
+		{
+			name:     "no headings",
+			fragment: "<p>Just a paragraph.</p>",
+			wantHTML: "<p>Just a paragraph.</p>",
+			wantTOC:  []models.TOCEntry{},
+		},
+		{
+			name:     "duplicate headings get numbered",
+			fragment: "<h2>Intro</h2><h2>Intro</h2><h2>Intro 2</h2>",
+			wantHTML: `<h2 id="intro">Intro</h2><h2 id="intro-2">Intro</h2><h2 id="intro-2-2">Intro 2</h2>`,
+			wantTOC: []models.TOCEntry{
+				{ID: "intro", Text: "Intro", Level: 2},
+				{ID: "intro-2", Text: "Intro", Level: 2},
+				{ID: "intro-2-2", Text: "Intro 2", Level: 2},
+			},
+		},
+		{
+			name:     "unicode anchors",
+			fragment: "<h2>Café &amp; Ünïcode</h2><h2>日本語 ガイド</h2>",
+			wantHTML: `<h2 id="café-ünïcode">Café &amp; Ünïcode</h2><h2 id="日本語-ガイド">日本語 ガイド</h2>`,
+			wantTOC: []models.TOCEntry{
+				{ID: "café-ünïcode", Text: "Café & Ünïcode", Level: 2},
+				{ID: "日本語-ガイド", Text: "日本語 ガイド", Level: 2},
+			},
+		},
+		{
+			name:     "headings without letters",
+			fragment: "<h2>!!!</h2><h2></h2>",
+			wantHTML: `<h2 id="section">!!!</h2><h2 id="section-2"></h2>`,
+			wantTOC: []models.TOCEntry{
+				{ID: "section", Text: "!!!", Level: 2},
+				{ID: "section-2", Text: "", Level: 2},
+			},
+		},
+		{
+			name:     "inline markup is kept, the text is flattened",
+			fragment: "<h2>Hello <em>big</em>\n  world</h2>",
+			wantHTML: `<h2 id="hello-big-world">Hello <em>big</em>` + "\n" + `  world</h2>`,
+			wantTOC:  []models.TOCEntry{{ID: "hello-big-world", Text: "Hello big world", Level: 2}},
+		},
+		{
+			name:     "an existing id is replaced, other attributes kept",
+			fragment: `<h2 class="title" id="old">Title</h2>`,
+			wantHTML: `<h2 class="title" id="title">Title</h2>`,
+			wantTOC:  []models.TOCEntry{{ID: "title", Text: "Title", Level: 2}},
+		},
+		{
+			name:     "nesting",
+			fragment: "<h1>A</h1><h2>B</h2><h3>C</h3><h2>D</h2><h4>E</h4><h1>F</h1>",
+			wantHTML: `<h1 id="a">A</h1><h2 id="b">B</h2><h3 id="c">C</h3><h2 id="d">D</h2><h4 id="e">E</h4><h1 id="f">F</h1>`,
+			wantTOC: []models.TOCEntry{
+				{ID: "a", Text: "A", Level: 1, Children: []models.TOCEntry{
+					{ID: "b", Text: "B", Level: 2, Children: []models.TOCEntry{
+						{ID: "c", Text: "C", Level: 3},
+					}},
+					{ID: "d", Text: "D", Level: 2, Children: []models.TOCEntry{
+						{ID: "e", Text: "E", Level: 4}, // Skipped levels nest under the closest heading above
+					}},
+				}},
+				{ID: "f", Text: "F", Level: 1},
+			},
+		},
+		{
+			name:     "a deeper first heading is still a root",
+			fragment: "<h3>Deep</h3><h2>Shallow</h2>",
+			wantHTML: `<h3 id="deep">Deep</h3><h2 id="shallow">Shallow</h2>`,
+			wantTOC: []models.TOCEntry{
+				{ID: "deep", Text: "Deep", Level: 3},
+				{ID: "shallow", Text: "Shallow", Level: 2},
+			},
+		},
+		{
+			name:     "an unclosed heading is left as it was",
+			fragment: "<p>Intro</p><h2>Never closed",
+			wantHTML: "<p>Intro</p><h2>Never closed",
+			wantTOC:  []models.TOCEntry{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, toc := AddHeadingAnchors(tt.fragment)
+			if html != tt.wantHTML {
+				t.Errorf("AddHeadingAnchors(%q) HTML =\n%s\nwant\n%s", tt.fragment, html, tt.wantHTML)
+			}
+			if !reflect.DeepEqual(toc, tt.wantTOC) {
+				t.Errorf("AddHeadingAnchors(%q) TOC =\n%+v\nwant\n%+v", tt.fragment, toc, tt.wantTOC)
+			}
+		})
+	}
+}
+
+func TestAddHeadingAnchorsIsStable(t *testing.T) {
+	fragment := "<h2>Setup</h2><p>Text</p><h2>Setup</h2>"
+	first, _ := AddHeadingAnchors(fragment)
+	second, _ := AddHeadingAnchors(fragment)
+	if first != second {
+		t.Errorf("AddHeadingAnchors is not deterministic:\n%s\n%s", first, second)
+	}
+}